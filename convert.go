@@ -0,0 +1,225 @@
+package structs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var mapperType = reflect.TypeOf((*Mapper)(nil)).Elem()
+
+// ConverterFunc converts a value into its representation in Map and Values.
+type ConverterFunc func(v reflect.Value) (interface{}, error)
+
+// Mapper is implemented by types which control their own representation in
+// Map and Values. The returned map is used in place of the converted struct,
+// so it can be flattened with the "flatten" option too.
+type Mapper interface {
+	StructMap() (map[string]interface{}, error)
+}
+
+// RegisterConverter registers fn to convert values of the type t in Map,
+// Values and all of their nested structs, maps and slices. Converters take
+// precedence over Mapper and encoding.TextMarshaler implementations. A
+// converter registered for a non pointer type is used for pointers to that
+// type as well. Registering a nil fn removes the converter. Example:
+//
+//   s := structs.New(server)
+//   s.RegisterConverter(reflect.TypeOf(time.Time{}), func(v reflect.Value) (interface{}, error) {
+//       return v.Interface().(time.Time).Unix(), nil
+//   })
+func (s *Struct) RegisterConverter(t reflect.Type, fn ConverterFunc) {
+	// copy the registry, as it's shared with the nested *Struct instances
+	converters := make(map[reflect.Type]ConverterFunc, len(s.converters)+1)
+	for k, v := range s.converters {
+		converters[k] = v
+	}
+
+	if fn == nil {
+		delete(converters, t)
+	} else {
+		converters[t] = fn
+	}
+
+	s.converters = converters
+}
+
+// convert converts v with a registered converter, a Mapper or a
+// TextMarshaler implementation other than time.Time and the ones promoted from
+// embedded fields. The returned boolean reports whether any of them was used.
+func (s *Struct) convert(v reflect.Value) (interface{}, bool, error) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil, false, nil
+	}
+
+	if fn, ok := s.converters[v.Type()]; ok {
+		out, err := fn(readable(v))
+		return out, err == nil, err
+	}
+
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		if fn, ok := s.converters[v.Type().Elem()]; ok {
+			out, err := fn(readable(v.Elem()))
+			return out, err == nil, err
+		}
+	}
+
+	if m, ok := implementer(v, mapperType); ok {
+		out, err := m.(Mapper).StructMap()
+		return out, err == nil, err
+	}
+
+	// time.Time is kept as it is, like in the previous versions
+	if s.UseTextMarshaler && elemType(v.Type()) != timeType &&
+		!embedsMethods(v.Type(), textMarshalerType) {
+		if m, ok := implementer(v, textMarshalerType); ok {
+			out, err := m.(encoding.TextMarshaler).MarshalText()
+			return string(out), err == nil, err
+		}
+	}
+
+	return nil, false, nil
+}
+
+// implementer returns v as an interface{} if its type implements iface. If
+// only the pointer type implements it, a pointer to v, or to a copy of v if
+// it's not addressable, is returned. The boolean is false for nil values and
+// values of unexported fields.
+func implementer(v reflect.Value, iface reflect.Type) (interface{}, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+
+	t := v.Type()
+	if t.Implements(iface) {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			// don't call the method on nil values, they may not handle it
+			if v.IsNil() {
+				return nil, false
+			}
+		}
+
+		return v.Interface(), true
+	}
+
+	if t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(iface) {
+		if !v.CanAddr() {
+			c := reflect.New(t).Elem()
+			c.Set(v)
+			v = c
+		}
+
+		return v.Addr().Interface(), true
+	}
+
+	return nil, false
+}
+
+// embedsMethods returns true if the struct type t, or the struct type t
+// points to, gets the methods of iface from one of its embedded fields, such
+// as a struct embedding time.Time gets MarshalText. Such structs are iterated
+// instead of being converted with these methods, which would drop their other
+// fields.
+func embedsMethods(t, iface reflect.Type) bool {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+
+		if f.Type.Implements(iface) ||
+			(f.Type.Kind() != reflect.Ptr && reflect.PtrTo(f.Type).Implements(iface)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readable returns a copy of v if it's the value of an unexported field, so
+// converters can call Interface on it.
+func readable(v reflect.Value) reflect.Value {
+	if v.CanInterface() {
+		return v
+	}
+
+	if c, ok := copyValue(v, make(map[uintptr]reflect.Value)); ok {
+		return c
+	}
+
+	return v
+}
+
+// containsStructs returns true if values of the type t are or contain structs
+// which have to be converted, either directly or through pointers, slices,
+// arrays and maps. Interfaces may contain structs if ExpandInterfaces is
+// enabled.
+func (s *Struct) containsStructs(t reflect.Type) bool {
+	return s.containsStructsSeen(t, make(map[reflect.Type]bool))
+}
+
+func (s *Struct) containsStructsSeen(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if _, ok := s.converters[t]; ok || t.Implements(mapperType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Interface:
+		return s.ExpandInterfaces
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		// recursive types, ie: type List []List
+		if seen[t] {
+			return false
+		}
+		seen[t] = true
+
+		return s.containsStructsSeen(t.Elem(), seen)
+	}
+
+	return false
+}
+
+// mapKey returns the string form of a map key. Strings are used as they are,
+// encoding.TextMarshaler and fmt.Stringer implementations are used if
+// available and numbers and booleans are formatted.
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+
+	if m, ok := implementer(readable(k), textMarshalerType); ok {
+		if b, err := m.(encoding.TextMarshaler).MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	if m, ok := implementer(readable(k), stringerType); ok {
+		return m.(fmt.Stringer).String()
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(k.Float(), 'g', -1, k.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(k.Bool())
+	}
+
+	return fmt.Sprint(readValue(k))
+}
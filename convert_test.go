@@ -0,0 +1,189 @@
+package structs
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type convertPoint struct {
+	X, Y int
+}
+
+func (p convertPoint) StructMap() (map[string]interface{}, error) {
+	return map[string]interface{}{"xy": strconv.Itoa(p.X) + "," + strconv.Itoa(p.Y)}, nil
+}
+
+type convertBroken struct {
+	A int
+}
+
+func (b *convertBroken) StructMap() (map[string]interface{}, error) {
+	return nil, errors.New("broken")
+}
+
+func TestRegisterConverter(t *testing.T) {
+	type Event struct {
+		Name    string
+		At      time.Time
+		AtPtr   *time.Time
+		History []time.Time
+	}
+
+	at := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	e := Event{Name: "start", At: at, AtPtr: &at, History: []time.Time{at}}
+
+	s := New(e)
+	s.RegisterConverter(reflect.TypeOf(time.Time{}), func(v reflect.Value) (interface{}, error) {
+		return v.Interface().(time.Time).Unix(), nil
+	})
+
+	m, err := s.MapErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m["At"] != at.Unix() {
+		t.Errorf("Map should use the registered converter, got: %v", m["At"])
+	}
+
+	if m["AtPtr"] != at.Unix() {
+		t.Errorf("Map should use the converter for pointers, got: %v", m["AtPtr"])
+	}
+
+	expected := []interface{}{"start", at.Unix(), at.Unix(), []time.Time{at}}
+	if v := s.Values(); !reflect.DeepEqual(v, expected) {
+		t.Errorf("Values should return %v, got: %v", expected, v)
+	}
+
+	// the converters of s are not shared with other instances
+	if _, ok := Map(e)["At"].(time.Time); !ok {
+		t.Error("Map should not use converters of another Struct")
+	}
+}
+
+func TestRegisterConverter_Nested(t *testing.T) {
+	type Inner struct {
+		ID int
+	}
+
+	type Outer struct {
+		Inner  Inner
+		Slice  []Inner
+		Values map[string]Inner
+	}
+
+	s := New(Outer{
+		Inner:  Inner{ID: 1},
+		Slice:  []Inner{{ID: 2}},
+		Values: map[string]Inner{"a": {ID: 3}},
+	})
+	s.RegisterConverter(reflect.TypeOf(Inner{}), func(v reflect.Value) (interface{}, error) {
+		return v.Field(0).Int(), nil
+	})
+
+	expected := map[string]interface{}{
+		"Inner":  int64(1),
+		"Slice":  []interface{}{int64(2)},
+		"Values": map[string]interface{}{"a": int64(3)},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+}
+
+func TestMapper(t *testing.T) {
+	type T struct {
+		Point  convertPoint
+		Flat   convertPoint `structs:",flatten"`
+		Broken convertBroken
+		Name   string
+	}
+
+	v := T{Point: convertPoint{1, 2}, Flat: convertPoint{3, 4}, Name: "gopher"}
+
+	m, err := New(&v).MapErr()
+	if err == nil {
+		t.Error("MapErr should return the error of the Mapper")
+	}
+
+	expected := map[string]interface{}{
+		"Point": map[string]interface{}{"xy": "1,2"},
+		"xy":    "3,4",
+		"Name":  "gopher",
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("MapErr should return %v, got: %v", expected, m)
+	}
+
+	if _, err := New(&v).ValuesErr(); err == nil {
+		t.Error("ValuesErr should return the error of the Mapper")
+	}
+}
+
+func TestUseTextMarshaler(t *testing.T) {
+	type T struct {
+		IP net.IP
+		At time.Time
+	}
+
+	at := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	v := T{IP: net.ParseIP("127.0.0.1"), At: at}
+
+	m := Map(v)
+
+	if m["IP"] != "127.0.0.1" {
+		t.Errorf("Map should use the TextMarshaler of net.IP by default, got: %v", m["IP"])
+	}
+
+	if m["At"] != at {
+		t.Errorf("Map should keep time.Time as it is, got: %v", m["At"])
+	}
+
+	if vals := Values(v); vals[0] != "127.0.0.1" {
+		t.Errorf("Values should use the TextMarshaler of net.IP by default, got: %v", vals[0])
+	}
+
+	s := New(v)
+	s.UseTextMarshaler = false
+
+	if _, ok := s.Map()["IP"].(net.IP); !ok {
+		t.Error("Map should not use TextMarshaler if it's disabled")
+	}
+
+	if _, ok := NewWith(v, WithTextMarshaler(false)).Map()["IP"].(net.IP); !ok {
+		t.Error("Map should not use TextMarshaler if it's disabled with an option")
+	}
+}
+
+func TestUseTextMarshaler_EmbeddedTime(t *testing.T) {
+	type Event struct {
+		time.Time
+		Name string
+	}
+
+	type T struct {
+		Event Event
+	}
+
+	at := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	v := T{Event: Event{Time: at, Name: "n"}}
+
+	expected := map[string]interface{}{
+		"Event": map[string]interface{}{"Time": at, "Name": "n"},
+	}
+	if m := Map(v); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should iterate a struct embedding time.Time, got: %v", m)
+	}
+
+	// Values iterates time.Time too, which has no exported fields
+	values := []interface{}{"n"}
+	if vals := Values(v); !reflect.DeepEqual(vals, values) {
+		t.Errorf("Values should iterate a struct embedding time.Time, got: %v", vals)
+	}
+}
@@ -0,0 +1,305 @@
+package structs
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	errNotSliceOfStruct = errors.New("value is not a slice of structs")
+
+	timeType          = reflect.TypeOf(time.Time{})
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// CSVWriter writes slices of structs as CSV records. The header is derived
+// from the struct type, so every row has the same columns regardless of the
+// values it holds.
+type CSVWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+
+	// TagName is the struct tag used to name the columns. It defaults to
+	// DefaultTagName.
+	TagName string
+
+	// TimeFormat is the layout used to format time.Time values. It defaults
+	// to time.RFC3339.
+	TimeFormat string
+}
+
+// NewCSVWriter returns a new *CSVWriter that writes to w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{
+		w:          csv.NewWriter(w),
+		TagName:    DefaultTagName,
+		TimeFormat: time.RFC3339,
+	}
+}
+
+// Encode writes the given slice of structs (either []T or []*T) as CSV. The
+// header is written on the first call only. Column names are the field names,
+// which can be changed with the struct field's tag value. Nested structs are
+// flattened into dotted columns. Example:
+//
+//   // Columns are "name", "address.city" and "address.zip"
+//   type Person struct {
+//       Name    string  `structs:"name"`
+//       Address Address `structs:"address"`
+//   }
+//
+// A tag value with the option of "flatten" writes the nested fields without
+// the dotted prefix and "omitnested" writes the nested struct as a single
+// column. Fields tagged with "-" are ignored. The "omitempty" option has no
+// effect on the columns, an empty value is written as an empty cell instead.
+// An error is returned for recursive types, such as a Parent field of the
+// struct's own type, unless the recursive field has the "omitnested" option.
+//
+// time.Time values are formatted with TimeFormat, types implementing
+// encoding.TextMarshaler or fmt.Stringer are formatted with their methods,
+// preferring MarshalText if both are implemented. A nil pointer is written as
+// an empty cell. Note that the output of String can't be read back by
+// CSVReader, as only encoding.TextUnmarshaler is used for decoding.
+func (c *CSVWriter) Encode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return errNotSliceOfStruct
+	}
+
+	elem := rv.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return errNotSliceOfStruct
+	}
+
+	columns, err := csvColumns(elem, c.TagName)
+	if err != nil {
+		return err
+	}
+
+	if !c.wroteHeader {
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = col.name
+		}
+
+		if err := c.w.Write(header); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	record := make([]string, len(columns))
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		for j, col := range columns {
+			s, err := formatValue(fieldByIndex(row, col.index), c.TimeFormat)
+			if err != nil {
+				return fmt.Errorf("row %d, column %q: %s", i+1, col.name, err)
+			}
+			record[j] = s
+		}
+
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// formatValue returns the textual form of v. time.Time values are formatted
+// with the given layout and nil pointers result in an empty string.
+func formatValue(v reflect.Value, timeFormat string) (string, error) {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return "", nil
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(timeFormat), nil
+	}
+
+	if v.CanInterface() {
+		x := v.Interface()
+		if v.CanAddr() && !v.Type().Implements(stringerType) &&
+			!v.Type().Implements(textMarshalerType) {
+			x = v.Addr().Interface()
+		}
+
+		switch x := x.(type) {
+		case encoding.TextMarshaler:
+			b, err := x.MarshalText()
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		case fmt.Stringer:
+			return x.String(), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// csvColumn describes a single CSV column and the path to its field.
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// csvColumns returns the columns for the given struct type, flattening nested
+// structs into dotted names. An error is returned for recursive types, as
+// they would expand into infinitely many columns.
+func csvColumns(t reflect.Type, tagName string) ([]csvColumn, error) {
+	var columns []csvColumn
+	err := appendCSVColumns(&columns, t, tagName, "", nil, map[reflect.Type]bool{t: true})
+	return columns, err
+}
+
+// appendCSVColumns appends the columns of the struct type t. The path map
+// contains the struct types which are currently being expanded.
+func appendCSVColumns(columns *[]csvColumn, t reflect.Type, tagName, prefix string, index []int, path map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// we can't access the value of unexported fields
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, tagOpts := parseTag(tag)
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && !tagOpts.Has("omitnested") &&
+			!tagOpts.Has("string") && !isLeafStruct(ft) {
+			if path[ft] {
+				return fmt.Errorf("field %s: recursive type %s, use the omitnested option", field.Name, ft)
+			}
+
+			nestedPrefix := prefix + name + "."
+			if tagOpts.Has("flatten") {
+				nestedPrefix = prefix
+			}
+
+			path[ft] = true
+			err := appendCSVColumns(columns, ft, tagName, nestedPrefix, fieldIndex, path)
+			delete(path, ft)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		*columns = append(*columns, csvColumn{
+			name:  prefix + name,
+			index: fieldIndex,
+		})
+	}
+
+	return nil
+}
+
+// isLeafStruct returns true if the given struct type should be treated as a
+// single value instead of being expanded into its fields, ie: time.Time.
+// Structs which get String or MarshalText from an embedded field are expanded.
+func isLeafStruct(t reflect.Type) bool {
+	if t == timeType || !hasExportedFields(t) {
+		return true
+	}
+
+	return implementsText(t) && !embedsMethods(t, stringerType) &&
+		!embedsMethods(t, textMarshalerType)
+}
+
+// hasExportedFields returns true if the struct type t has at least one
+// exported field.
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// implementsText returns true if t or *t implements fmt.Stringer or
+// encoding.TextMarshaler.
+func implementsText(t reflect.Type) bool {
+	if t.Implements(stringerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+
+	if t.Kind() != reflect.Ptr {
+		p := reflect.PtrTo(t)
+		return p.Implements(stringerType) || p.Implements(textMarshalerType)
+	}
+
+	return false
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but it returns an invalid
+// value instead of panicking if it encounters a nil pointer.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+
+	return v
+}
@@ -0,0 +1,270 @@
+package structs
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errNotSlicePointer = errors.New("value is not a pointer to a slice of structs")
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// UnknownColumnPolicy defines how a CSVReader handles header columns that
+// don't map to any struct field.
+type UnknownColumnPolicy int
+
+const (
+	// IgnoreUnknownColumns skips columns that don't map to a field.
+	IgnoreUnknownColumns UnknownColumnPolicy = iota
+
+	// ErrorOnUnknownColumns makes Decode return an error for columns that
+	// don't map to a field.
+	ErrorOnUnknownColumns
+)
+
+// CSVError is returned by CSVReader.Decode if a cell can't be converted into
+// its field. Row and Column are 1-based, the header being the first row.
+type CSVError struct {
+	Row    int
+	Column int
+	Field  string
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	return fmt.Sprintf("row %d, column %d (%s): %s", e.Row, e.Column, e.Field, e.Err)
+}
+
+// CSVReader reads CSV records into slices of structs. It is the counterpart
+// of CSVWriter and uses the same column names. Values written by CSVWriter
+// are read back, except for types which only implement fmt.Stringer: custom
+// types round-trip only if they implement encoding.TextUnmarshaler.
+type CSVReader struct {
+	r      *csv.Reader
+	header []string
+	row    int
+
+	// TagName is the struct tag used to match the columns. It defaults to
+	// DefaultTagName.
+	TagName string
+
+	// TimeFormat is the layout used to parse time.Time values. It defaults
+	// to time.RFC3339.
+	TimeFormat string
+
+	// CaseInsensitive matches the header columns to the field names
+	// regardless of their case.
+	CaseInsensitive bool
+
+	// UnknownColumns defines how columns without a matching field are
+	// handled. It defaults to IgnoreUnknownColumns.
+	UnknownColumns UnknownColumnPolicy
+}
+
+// NewCSVReader returns a new *CSVReader that reads from r.
+func NewCSVReader(r io.Reader) *CSVReader {
+	return &CSVReader{
+		r:          csv.NewReader(r),
+		TagName:    DefaultTagName,
+		TimeFormat: time.RFC3339,
+	}
+}
+
+// Decode reads the header and all remaining records and appends them to the
+// slice pointed to by v, which must be a *[]T or *[]*T. Columns are matched to
+// the fields the same way CSVWriter names them, nested structs are read from
+// dotted columns. Empty cells leave the field at its zero value and nil
+// pointers are only allocated for non-empty cells.
+//
+// Types implementing encoding.TextUnmarshaler are decoded with their method,
+// time.Time values are parsed with TimeFormat. Conversion errors are returned
+// as *CSVError.
+func (c *CSVReader) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return errNotSlicePointer
+	}
+
+	slice := rv.Elem()
+	elem := slice.Type().Elem()
+	isPtr := elem.Kind() == reflect.Ptr
+	if isPtr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return errNotSlicePointer
+	}
+
+	if c.header == nil {
+		header, err := c.r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		c.header = header
+		c.row++
+	}
+
+	columns, err := c.match(elem)
+	if err != nil {
+		return err
+	}
+
+	for {
+		record, err := c.r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		c.row++
+
+		item := reflect.New(elem)
+		for i, cell := range record {
+			if i >= len(columns) || columns[i] == nil || cell == "" {
+				continue
+			}
+
+			field := allocFieldByIndex(item.Elem(), columns[i].index)
+			if err := c.parse(field, cell); err != nil {
+				return &CSVError{
+					Row:    c.row,
+					Column: i + 1,
+					Field:  columns[i].name,
+					Err:    err,
+				}
+			}
+		}
+
+		if isPtr {
+			slice.Set(reflect.Append(slice, item))
+		} else {
+			slice.Set(reflect.Append(slice, item.Elem()))
+		}
+	}
+
+	return nil
+}
+
+// match returns the column for every header entry. Entries without a matching
+// field are nil.
+func (c *CSVReader) match(t reflect.Type) ([]*csvColumn, error) {
+	columns, err := csvColumns(t, c.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*csvColumn, len(columns))
+	for i := range columns {
+		name := columns[i].name
+		if c.CaseInsensitive {
+			name = strings.ToLower(name)
+		}
+		byName[name] = &columns[i]
+	}
+
+	matched := make([]*csvColumn, len(c.header))
+	for i, name := range c.header {
+		if c.CaseInsensitive {
+			name = strings.ToLower(name)
+		}
+
+		col, ok := byName[name]
+		if !ok && c.UnknownColumns == ErrorOnUnknownColumns {
+			return nil, fmt.Errorf("column %d: unknown column %q", i+1, c.header[i])
+		}
+		matched[i] = col
+	}
+
+	return matched, nil
+}
+
+// parse converts the given string into v's type and sets it.
+func (c *CSVReader) parse(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		t, err := time.Parse(c.TimeFormat, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(s))
+			return nil
+		}
+
+		return fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+
+	return nil
+}
+
+// allocFieldByIndex is like reflect.Value.FieldByIndex, but it allocates nil
+// pointers along the way so the returned field is always settable.
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+
+	return v
+}
@@ -0,0 +1,194 @@
+package structs
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvRecord struct {
+	Name    string      `structs:"name"`
+	Age     int         `structs:"age"`
+	Score   float64     `structs:"score"`
+	Active  bool        `structs:"active"`
+	Born    time.Time   `structs:"born"`
+	Address csvAddress  `structs:"address"`
+	Work    *csvAddress `structs:"work"`
+}
+
+func TestCSVReader_Decode(t *testing.T) {
+	in := "name,age,score,active,born,address.city,work.city\n" +
+		"fatih,30,1.5,true,1990-01-02T03:04:05Z,Istanbul,Berlin\n" +
+		"arslan,,,,,Ankara,\n"
+
+	var out []csvRecord
+	if err := NewCSVReader(strings.NewReader(in)).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []csvRecord{
+		{
+			Name:    "fatih",
+			Age:     30,
+			Score:   1.5,
+			Active:  true,
+			Born:    time.Date(1990, 1, 2, 3, 4, 5, 0, time.UTC),
+			Address: csvAddress{City: "Istanbul"},
+			Work:    &csvAddress{City: "Berlin"},
+		},
+		{
+			Name:    "arslan",
+			Address: csvAddress{City: "Ankara"},
+		},
+	}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("Decode should return %+v, got: %+v", expected, out)
+	}
+}
+
+func TestCSVReader_RoundTrip(t *testing.T) {
+	in := []*csvRecord{
+		{Name: "fatih", Age: 30, Work: &csvAddress{City: "Berlin", Zip: "10115"}},
+		{Name: "arslan", Score: 2.25, Address: csvAddress{City: "Ankara"}},
+	}
+
+	var buf bytes.Buffer
+	if err := NewCSVWriter(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []*csvRecord
+	if err := NewCSVReader(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != len(in) {
+		t.Fatalf("Decode should return %d records, got: %d", len(in), len(out))
+	}
+
+	for i := range in {
+		// the zero time is written explicitly, so compare with its instant
+		if !out[i].Born.Equal(in[i].Born) {
+			t.Errorf("record %d: Born should be %v, got: %v", i, in[i].Born, out[i].Born)
+		}
+		out[i].Born = in[i].Born
+
+		if !reflect.DeepEqual(out[i], in[i]) {
+			t.Errorf("record %d: Decode should return %+v, got: %+v", i, in[i], out[i])
+		}
+	}
+}
+
+func TestCSVReader_CaseInsensitive(t *testing.T) {
+	in := "NAME,Age\nfatih,30\n"
+
+	var out []csvRecord
+	r := NewCSVReader(strings.NewReader(in))
+	if err := r.Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out[0].Name != "" || out[0].Age != 0 {
+		t.Errorf("Decode should not match columns case sensitively, got: %+v", out[0])
+	}
+
+	out = nil
+	r = NewCSVReader(strings.NewReader(in))
+	r.CaseInsensitive = true
+	if err := r.Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out[0].Name != "fatih" || out[0].Age != 30 {
+		t.Errorf("Decode should match columns case insensitively, got: %+v", out[0])
+	}
+}
+
+func TestCSVReader_UnknownColumns(t *testing.T) {
+	in := "name,nickname\nfatih,gopher\n"
+
+	var out []csvRecord
+	if err := NewCSVReader(strings.NewReader(in)).Decode(&out); err != nil {
+		t.Errorf("Decode should ignore unknown columns by default, got: %v", err)
+	}
+
+	r := NewCSVReader(strings.NewReader(in))
+	r.UnknownColumns = ErrorOnUnknownColumns
+	if err := r.Decode(&out); err == nil {
+		t.Error("Decode should return an error for unknown columns")
+	}
+}
+
+func TestCSVReader_Error(t *testing.T) {
+	in := "name,age\nfatih,30\narslan,old\n"
+
+	var out []csvRecord
+	err := NewCSVReader(strings.NewReader(in)).Decode(&out)
+
+	csvErr, ok := err.(*CSVError)
+	if !ok {
+		t.Fatalf("Decode should return a *CSVError, got: %T", err)
+	}
+
+	if csvErr.Row != 3 || csvErr.Column != 2 || csvErr.Field != "age" {
+		t.Errorf("CSVError should point to row 3, column 2 (age), got: %v", csvErr)
+	}
+}
+
+func TestCSVReader_NonSlicePointer(t *testing.T) {
+	r := NewCSVReader(strings.NewReader("name\nfatih\n"))
+
+	if err := r.Decode([]csvRecord{}); err != errNotSlicePointer {
+		t.Errorf("Decode should return %v for a non pointer, got: %v", errNotSlicePointer, err)
+	}
+
+	var ints []int
+	if err := r.Decode(&ints); err != errNotSlicePointer {
+		t.Errorf("Decode should return %v for a slice of ints, got: %v", errNotSlicePointer, err)
+	}
+}
+
+func TestCSVReader_RecursiveType(t *testing.T) {
+	var nodes []csvNode
+	if err := NewCSVReader(strings.NewReader("name\na\n")).Decode(&nodes); err == nil {
+		t.Error("Decode should return an error for a recursive type")
+	}
+}
+
+type csvColor struct{ name string }
+
+func (c csvColor) String() string { return "color " + c.name }
+
+func (c csvColor) MarshalText() ([]byte, error) { return []byte(c.name), nil }
+
+func (c *csvColor) UnmarshalText(text []byte) error {
+	c.name = string(text)
+	return nil
+}
+
+func TestCSVReader_TextMarshalerRoundTrip(t *testing.T) {
+	type paint struct {
+		Color csvColor `structs:"color"`
+	}
+
+	var buf bytes.Buffer
+	if err := NewCSVWriter(&buf).Encode([]paint{{Color: csvColor{"red"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "color\nred\n" {
+		t.Errorf("Encode should prefer MarshalText over String, got: %q", got)
+	}
+
+	var out []paint
+	if err := NewCSVReader(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != 1 || out[0].Color.name != "red" {
+		t.Errorf("Decode should read back the MarshalText output, got: %+v", out)
+	}
+}
@@ -0,0 +1,199 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type csvAddress struct {
+	City string `structs:"city"`
+	Zip  string `structs:"zip,omitempty"`
+}
+
+type csvLevel int
+
+func (l csvLevel) String() string {
+	if l > 0 {
+		return "high"
+	}
+	return "low"
+}
+
+type csvPerson struct {
+	Name    string      `structs:"name"`
+	Age     int         `structs:"age,omitempty"`
+	Score   float64     `structs:"score"`
+	Level   csvLevel    `structs:"level"`
+	Born    time.Time   `structs:"born"`
+	Address csvAddress  `structs:"address"`
+	Work    *csvAddress `structs:"work"`
+	Secret  string      `structs:"-"`
+	hidden  string
+}
+
+func TestCSVWriter_Encode(t *testing.T) {
+	born := time.Date(1990, 1, 2, 3, 4, 5, 0, time.UTC)
+	people := []csvPerson{
+		{
+			Name:    "fatih",
+			Age:     30,
+			Score:   1.5,
+			Level:   1,
+			Born:    born,
+			Address: csvAddress{City: "Istanbul", Zip: "34000"},
+			Work:    &csvAddress{City: "Berlin"},
+			Secret:  "secret",
+		},
+		{
+			Name:    "arslan",
+			Address: csvAddress{City: "Ankara"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewCSVWriter(&buf).Encode(people); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "name,age,score,level,born,address.city,address.zip,work.city,work.zip\n" +
+		"fatih,30,1.5,high,1990-01-02T03:04:05Z,Istanbul,34000,Berlin,\n" +
+		"arslan,0,0,low,0001-01-01T00:00:00Z,Ankara,,,\n"
+
+	if buf.String() != expected {
+		t.Errorf("Encode should write\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestCSVWriter_EncodePointers(t *testing.T) {
+	type T struct {
+		A string
+		B int
+	}
+
+	w := new(bytes.Buffer)
+	c := NewCSVWriter(w)
+
+	if err := c.Encode([]*T{{A: "a", B: 1}, nil}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the header is only written once
+	if err := c.Encode([]*T{{A: "b", B: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "A,B\na,1\n,\nb,2\n"
+	if w.String() != expected {
+		t.Errorf("Encode should write %q, got: %q", expected, w.String())
+	}
+}
+
+func TestCSVWriter_FlattenAndOmitNested(t *testing.T) {
+	type Inner struct {
+		X int
+		Y int
+	}
+
+	type T struct {
+		A Inner `structs:",flatten"`
+		B Inner `structs:"b,omitnested"`
+	}
+
+	var buf bytes.Buffer
+	c := NewCSVWriter(&buf)
+
+	if err := c.Encode([]T{{A: Inner{1, 2}, B: Inner{3, 4}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "X,Y,b\n1,2,{3 4}\n"
+	if buf.String() != expected {
+		t.Errorf("Encode should write %q, got: %q", expected, buf.String())
+	}
+}
+
+func TestCSVWriter_EmbeddedTime(t *testing.T) {
+	type Event struct {
+		time.Time `structs:"at"`
+		Name      string `structs:"name"`
+	}
+
+	type T struct {
+		Event Event `structs:"event"`
+	}
+
+	var buf bytes.Buffer
+	c := NewCSVWriter(&buf)
+	c.TimeFormat = "2006-01-02"
+
+	at := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	if err := c.Encode([]T{{Event: Event{Time: at, Name: "n"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "event.at,event.name\n2018-10-10,n\n"
+	if buf.String() != expected {
+		t.Errorf("Encode should write %q, got: %q", expected, buf.String())
+	}
+}
+
+func TestCSVWriter_TimeFormatAndTagName(t *testing.T) {
+	type T struct {
+		At time.Time `csv:"at"`
+	}
+
+	var buf bytes.Buffer
+	c := NewCSVWriter(&buf)
+	c.TagName = "csv"
+	c.TimeFormat = "2006-01-02"
+
+	at := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	if err := c.Encode([]T{{At: at}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "at\n2018-10-10\n"
+	if buf.String() != expected {
+		t.Errorf("Encode should write %q, got: %q", expected, buf.String())
+	}
+}
+
+func TestCSVWriter_NonSlice(t *testing.T) {
+	c := NewCSVWriter(new(bytes.Buffer))
+
+	if err := c.Encode(csvPerson{}); err != errNotSliceOfStruct {
+		t.Errorf("Encode should return %v for a non slice, got: %v", errNotSliceOfStruct, err)
+	}
+
+	if err := c.Encode([]int{1}); err != errNotSliceOfStruct {
+		t.Errorf("Encode should return %v for a slice of ints, got: %v", errNotSliceOfStruct, err)
+	}
+}
+
+type csvNode struct {
+	Name   string   `structs:"name"`
+	Parent *csvNode `structs:"parent"`
+}
+
+func TestCSVWriter_RecursiveType(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewCSVWriter(&buf).Encode([]csvNode{{Name: "a"}}); err == nil {
+		t.Error("Encode should return an error for a recursive type")
+	}
+
+	type Node struct {
+		Name   string `structs:"name"`
+		Parent *Node  `structs:"parent,omitnested"`
+	}
+
+	buf.Reset()
+	if err := NewCSVWriter(&buf).Encode([]Node{{Name: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := buf.String(); out != "name,parent\na,\n" {
+		t.Errorf("Encode should write a single column for omitnested, got: %q", out)
+	}
+}
@@ -0,0 +1,62 @@
+package structs
+
+import "reflect"
+
+// ref identifies a pointer, map or slice which is currently being iterated,
+// so we can detect cycles. The type is part of the key, as a pointer to a
+// struct and a pointer to its first field have the same address.
+type ref struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// walk returns s ready to iterate its fields recursively. Nested *Struct
+// instances share the visited references of their parent, while a top level
+// *Struct gets a new set, which contains the struct itself if it was passed
+// as a pointer.
+func (s *Struct) walk() *Struct {
+	if s.visited != nil {
+		return s
+	}
+
+	n := *s
+	n.visited = make(map[ref]bool)
+
+	if v := reflect.ValueOf(s.raw); v.Kind() == reflect.Ptr && !v.IsNil() {
+		n.visited[ref{ptr: v.Pointer(), typ: v.Type()}] = true
+	}
+
+	return &n
+}
+
+// enter marks the pointer, map or slice v as being iterated. The returned
+// function unmarks it again. The boolean is false if v is already being
+// iterated, which means it's referenced by one of its own fields or elements.
+func (s *Struct) enter(v reflect.Value) (func(), bool) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() || s.visited == nil {
+			return func() {}, true
+		}
+	default:
+		return func() {}, true
+	}
+
+	r := ref{ptr: v.Pointer(), typ: v.Type()}
+	if s.visited[r] {
+		return nil, false
+	}
+
+	s.visited[r] = true
+	return func() { delete(s.visited, r) }, true
+}
+
+// descend returns true if the nested structs of s can be iterated without
+// exceeding MaxDepth.
+func (s *Struct) descend() bool {
+	return s.MaxDepth <= 0 || s.depth+1 < s.MaxDepth
+}
@@ -0,0 +1,169 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+type cycleNode struct {
+	Name     string
+	Parent   *cycleNode
+	Children []*cycleNode
+}
+
+func newCycleTree() *cycleNode {
+	root := &cycleNode{Name: "root"}
+	child := &cycleNode{Name: "child", Parent: root}
+	root.Children = []*cycleNode{child}
+	return root
+}
+
+func TestMap_Cycle(t *testing.T) {
+	root := newCycleTree()
+
+	m, err := New(root).MapErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"Name":   "root",
+		"Parent": nil,
+		"Children": []interface{}{
+			map[string]interface{}{
+				"Name":     "child",
+				"Parent":   nil,
+				"Children": []interface{}{},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	// a self referencing map
+	type T struct {
+		Data map[string]interface{}
+	}
+
+	data := map[string]interface{}{"a": 1}
+	data["self"] = data
+
+	s := New(T{Data: data})
+	s.ExpandInterfaces = true
+
+	inner := s.Map()["Data"].(map[string]interface{})
+	if inner["a"] != 1 || inner["self"] != nil {
+		t.Errorf("Map should replace the cyclic map with nil, got: %v", inner)
+	}
+}
+
+func TestMap_SharedPointer(t *testing.T) {
+	type Inner struct {
+		ID int
+	}
+
+	type T struct {
+		A, B *Inner
+	}
+
+	in := &Inner{ID: 1}
+	m := Map(T{A: in, B: in})
+
+	expected := map[string]interface{}{"ID": 1}
+	if !reflect.DeepEqual(m["A"], expected) || !reflect.DeepEqual(m["B"], expected) {
+		t.Errorf("Map should convert shared pointers which are not cyclic, got: %v", m)
+	}
+}
+
+func TestValues_Cycle(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	n := &Node{Name: "a"}
+	n.Next = n
+
+	expected := []interface{}{"a", nil}
+	if v := Values(n); !reflect.DeepEqual(v, expected) {
+		t.Errorf("Values should return %v, got: %v", expected, v)
+	}
+}
+
+func TestZero_Cycle(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	n := &Node{}
+	n.Next = n
+
+	if IsZero(n) {
+		t.Error("IsZero should return false for a cyclic struct")
+	}
+
+	if !HasZero(n) {
+		t.Error("HasZero should return true for the empty Name")
+	}
+
+	n.Name = "a"
+	if HasZero(n) {
+		t.Error("HasZero should return false for a cyclic struct")
+	}
+
+	if paths := ZeroFields(n); len(paths) != 0 {
+		t.Errorf("ZeroFields should return no paths, got: %v", paths)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	type C struct {
+		Name string
+	}
+
+	type B struct {
+		C C
+	}
+
+	type A struct {
+		Name string
+		B    B
+	}
+
+	a := A{Name: "a", B: B{C: C{}}}
+
+	s := New(a)
+	s.MaxDepth = 2
+
+	expected := map[string]interface{}{
+		"Name": "a",
+		"B":    map[string]interface{}{"C": C{}},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values := []interface{}{"a", C{}}
+	if v := s.Values(); !reflect.DeepEqual(v, values) {
+		t.Errorf("Values should return %v, got: %v", values, v)
+	}
+
+	s.MaxDepth = 1
+
+	if m := s.Map(); !reflect.DeepEqual(m["B"], B{}) {
+		t.Errorf("Map should not convert B, got: %v", m["B"])
+	}
+
+	// B is compared as a whole
+	if !s.HasZero() {
+		t.Error("HasZero should return true")
+	}
+
+	if paths := s.ZeroFields(); !reflect.DeepEqual(paths, []string{"B"}) {
+		t.Errorf("ZeroFields should return [B], got: %v", paths)
+	}
+}
@@ -0,0 +1,177 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dialect is the SQL dialect of the statements created by CreateTableSQL.
+type Dialect int
+
+const (
+	// SQLiteDialect creates statements for SQLite.
+	SQLiteDialect Dialect = iota
+
+	// PostgresDialect creates statements for PostgreSQL.
+	PostgresDialect
+
+	// MySQLDialect creates statements for MySQL.
+	MySQLDialect
+)
+
+// tableNamer is implemented by types which define the name of their table.
+type tableNamer interface {
+	TableName() string
+}
+
+// CreateTableSQL returns a CREATE TABLE statement for the given struct in the
+// given dialect. The columns are the same as described in Columns. The table
+// name is the name of the struct type, unless it implements a "TableName()
+// string" method. The column types are derived from the field types and can
+// be tuned with the following options of the "db" key in the struct field's
+// tag value:
+//
+//   pk            the column is part of the primary key
+//   notnull       the column is NOT NULL
+//   unique        the column is UNIQUE
+//   default=expr  the column has the DEFAULT expr, which is used as it is
+//   size=n        strings are stored as VARCHAR(n)
+//   type=t        the column type is t, instead of the derived type
+//
+// Example:
+//
+//   type User struct {
+//       ID      int64     `db:"id,pk"`
+//       Email   string    `db:"email,notnull,unique,size=255"`
+//       Active  bool      `db:"active,default=1"`
+//       Created time.Time `db:"created_at"`
+//   }
+//
+// Nested structs have to be flattened with the "flatten" option, unless they
+// are handled by the database driver, such as time.Time and sql.NullString.
+// An error is returned for fields which can't be mapped to a column type and
+// for structs which contain themselves through embedded or flattened fields.
+// It panics if s's kind is not struct.
+func CreateTableSQL(s interface{}, dialect Dialect) (string, error) {
+	v := strctVal(s)
+
+	if dialect < SQLiteDialect || dialect > MySQLDialect {
+		return "", fmt.Errorf("unknown dialect %d", dialect)
+	}
+
+	table := v.Type().Name()
+	if n, ok := implementer(v, reflect.TypeOf((*tableNamer)(nil)).Elem()); ok {
+		table = n.(tableNamer).TableName()
+	}
+
+	columns, err := sqlColumnsErr(v.Type())
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	var pk []string
+
+	fmt.Fprintf(&buf, "CREATE TABLE %s (", dialect.quote(table))
+
+	for i, col := range columns {
+		typ, err := dialect.columnType(col)
+		if err != nil {
+			return "", fmt.Errorf("field %s: %s", col.field.Name, err)
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, "\n  %s %s", dialect.quote(col.name), typ)
+
+		if col.opts.Has("notnull") {
+			buf.WriteString(" NOT NULL")
+		}
+
+		if col.opts.Has("unique") {
+			buf.WriteString(" UNIQUE")
+		}
+
+		if def, ok := col.opts.Value("default"); ok {
+			buf.WriteString(" DEFAULT " + def)
+		}
+
+		if col.opts.Has("pk") {
+			pk = append(pk, dialect.quote(col.name))
+		}
+	}
+
+	if len(pk) > 0 {
+		fmt.Fprintf(&buf, ",\n  PRIMARY KEY (%s)", strings.Join(pk, ", "))
+	}
+
+	buf.WriteString("\n);")
+	return buf.String(), nil
+}
+
+// quote returns the quoted identifier name.
+func (d Dialect) quote(name string) string {
+	if d == MySQLDialect {
+		return "`" + strings.Replace(name, "`", "``", -1) + "`"
+	}
+
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// columnType returns the type of the column col.
+func (d Dialect) columnType(col sqlColumn) (string, error) {
+	if typ, ok := col.opts.Value("type"); ok {
+		return typ, nil
+	}
+
+	t := elemType(col.field.Type)
+
+	// nullable types of database/sql, such as sql.NullString, are stored as
+	// their first field
+	if t.Kind() == reflect.Struct && t != timeType && isSQLValue(t) {
+		valid, ok := t.FieldByName("Valid")
+		if !ok || valid.Type.Kind() != reflect.Bool || t.NumField() != 2 {
+			return "", fmt.Errorf("unsupported type %s, use the type option", t)
+		}
+		t = elemType(t.Field(0).Type)
+	}
+
+	if size, ok := col.opts.Value("size"); ok && t.Kind() == reflect.String {
+		return "VARCHAR(" + size + ")", nil
+	}
+
+	var types [3]string // types of SQLite, PostgreSQL and MySQL
+
+	switch {
+	case t == timeType:
+		types = [3]string{"DATETIME", "TIMESTAMP", "DATETIME"}
+	case t.Kind() == reflect.Bool:
+		types = [3]string{"INTEGER", "BOOLEAN", "BOOLEAN"}
+	case t.Kind() == reflect.Int8, t.Kind() == reflect.Int16, t.Kind() == reflect.Uint8:
+		types = [3]string{"INTEGER", "SMALLINT", "SMALLINT"}
+	case t.Kind() == reflect.Int32, t.Kind() == reflect.Uint16:
+		types = [3]string{"INTEGER", "INTEGER", "INT"}
+	case t.Kind() == reflect.Int, t.Kind() == reflect.Int64, t.Kind() == reflect.Uint32:
+		types = [3]string{"INTEGER", "BIGINT", "BIGINT"}
+	case t.Kind() == reflect.Uint, t.Kind() == reflect.Uint64:
+		// values above math.MaxInt64 don't fit into a signed BIGINT
+		types = [3]string{"INTEGER", "NUMERIC(20)", "BIGINT UNSIGNED"}
+	case t.Kind() == reflect.Float32:
+		types = [3]string{"REAL", "REAL", "FLOAT"}
+	case t.Kind() == reflect.Float64:
+		types = [3]string{"REAL", "DOUBLE PRECISION", "DOUBLE"}
+	case t.Kind() == reflect.String:
+		types = [3]string{"TEXT", "TEXT", "VARCHAR(255)"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		types = [3]string{"BLOB", "BYTEA", "BLOB"}
+	case t.Kind() == reflect.Struct:
+		return "", fmt.Errorf("nested struct %s, use the flatten option", t)
+	default:
+		return "", fmt.Errorf("unsupported type %s, use the type option", t)
+	}
+
+	return types[d], nil
+}
@@ -0,0 +1,156 @@
+package structs
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ddlAccount struct {
+	ID int64 `db:"id,pk"`
+}
+
+func (ddlAccount) TableName() string {
+	return "accounts"
+}
+
+func TestCreateTableSQL(t *testing.T) {
+	type Address struct {
+		City string `db:"city"`
+		Zip  string `db:"zip,size=5"`
+	}
+
+	type Base struct {
+		ID int64 `db:"id,pk"`
+	}
+
+	type User struct {
+		Base
+		Email   string         `db:"email,notnull,unique,size=128"`
+		Active  bool           `db:"active,default=1"`
+		Score   float64        `db:"score"`
+		Rank    int16          `db:"rank"`
+		Port    uint16         `db:"port"`
+		Hits    uint64         `db:"hits"`
+		Avatar  []byte         `db:"avatar"`
+		Nick    sql.NullString `db:"nick"`
+		Created *time.Time     `db:"created_at,notnull"`
+		Address Address        `db:"address,flatten"`
+		Data    string         `db:"data,type=JSON"`
+		Ignored string         `db:"-"`
+	}
+
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{SQLiteDialect, `CREATE TABLE "User" (
+  "id" INTEGER,
+  "email" VARCHAR(128) NOT NULL UNIQUE,
+  "active" INTEGER DEFAULT 1,
+  "score" REAL,
+  "rank" INTEGER,
+  "port" INTEGER,
+  "hits" INTEGER,
+  "avatar" BLOB,
+  "nick" TEXT,
+  "created_at" DATETIME NOT NULL,
+  "address_city" TEXT,
+  "address_zip" VARCHAR(5),
+  "data" JSON,
+  PRIMARY KEY ("id")
+);`},
+		{PostgresDialect, `CREATE TABLE "User" (
+  "id" BIGINT,
+  "email" VARCHAR(128) NOT NULL UNIQUE,
+  "active" BOOLEAN DEFAULT 1,
+  "score" DOUBLE PRECISION,
+  "rank" SMALLINT,
+  "port" INTEGER,
+  "hits" NUMERIC(20),
+  "avatar" BYTEA,
+  "nick" TEXT,
+  "created_at" TIMESTAMP NOT NULL,
+  "address_city" TEXT,
+  "address_zip" VARCHAR(5),
+  "data" JSON,
+  PRIMARY KEY ("id")
+);`},
+		{MySQLDialect, "CREATE TABLE `User` (\n" +
+			"  `id` BIGINT,\n" +
+			"  `email` VARCHAR(128) NOT NULL UNIQUE,\n" +
+			"  `active` BOOLEAN DEFAULT 1,\n" +
+			"  `score` DOUBLE,\n" +
+			"  `rank` SMALLINT,\n" +
+			"  `port` INT,\n" +
+			"  `hits` BIGINT UNSIGNED,\n" +
+			"  `avatar` BLOB,\n" +
+			"  `nick` VARCHAR(255),\n" +
+			"  `created_at` DATETIME NOT NULL,\n" +
+			"  `address_city` VARCHAR(255),\n" +
+			"  `address_zip` VARCHAR(5),\n" +
+			"  `data` JSON,\n" +
+			"  PRIMARY KEY (`id`)\n" +
+			");"},
+	}
+
+	for _, test := range tests {
+		out, err := CreateTableSQL(User{}, test.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out != test.expected {
+			t.Errorf("CreateTableSQL should return\n%s\ngot:\n%s", test.expected, out)
+		}
+	}
+
+	columns := []string{"id", "email", "active", "score", "rank", "port", "hits",
+		"avatar", "nick", "created_at", "address_city", "address_zip", "data"}
+	if c := Columns(User{}); !reflect.DeepEqual(c, columns) {
+		t.Errorf("Columns should return the flattened columns %v, got: %v", columns, c)
+	}
+}
+
+func TestCreateTableSQL_TableName(t *testing.T) {
+	out, err := CreateTableSQL(&ddlAccount{}, SQLiteDialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "CREATE TABLE \"accounts\" (\n  \"id\" INTEGER,\n  PRIMARY KEY (\"id\")\n);"
+	if out != expected {
+		t.Errorf("CreateTableSQL should return\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestCreateTableSQL_Errors(t *testing.T) {
+	type Inner struct {
+		A int
+	}
+
+	type Nested struct {
+		Inner Inner
+	}
+
+	if _, err := CreateTableSQL(Nested{}, SQLiteDialect); err == nil {
+		t.Error("CreateTableSQL should return an error for a nested struct")
+	}
+
+	type Unsupported struct {
+		C chan int
+	}
+
+	if _, err := CreateTableSQL(Unsupported{}, PostgresDialect); err == nil {
+		t.Error("CreateTableSQL should return an error for a channel")
+	}
+
+	if _, err := CreateTableSQL(sqlNode{}, MySQLDialect); err == nil {
+		t.Error("CreateTableSQL should return an error for a recursive type")
+	}
+
+	if _, err := CreateTableSQL(Inner{}, Dialect(42)); err == nil {
+		t.Error("CreateTableSQL should return an error for an unknown dialect")
+	}
+}
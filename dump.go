@@ -0,0 +1,248 @@
+package structs
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dump returns a deterministic, indented and human readable representation
+// of v. For more info refer to Fdump.
+func Dump(v interface{}) string {
+	var buf bytes.Buffer
+	d := &dumper{
+		buf:     &buf,
+		tagName: DefaultTagName,
+		visited: make(map[ref]bool),
+	}
+
+	d.dump(reflect.ValueOf(v), 0)
+	return buf.String()
+}
+
+// Fdump writes the representation of v to w. Nested structs, pointers, maps
+// and slices are printed one field or element per line. Map entries are
+// sorted by their keys and then by their values, so the output is the same for
+// equal values and can be used in test failure messages or logs. A pointer that refers back to a value which is currently
+// being printed is written as <cycle>.
+//
+// A struct tag with the content of "-" ignores that particular field. Example:
+//
+//   // Field is not printed
+//   Field string `structs:"-"`
+//   Field string `dump:"-"`
+//
+// A dump tag with the content of "redact" hides the value of that particular
+// field. Example:
+//
+//   // Field is printed as "Password: <redacted>"
+//   Password string `dump:"redact"`
+//
+// Note that only exported fields of a struct are printed.
+func Fdump(w io.Writer, v interface{}) error {
+	_, err := io.WriteString(w, Dump(v))
+	return err
+}
+
+type dumper struct {
+	buf     *bytes.Buffer
+	tagName string
+
+	// visited contains the pointers of the values which are currently being
+	// printed, so we can detect cycles.
+	visited map[ref]bool
+}
+
+func (d *dumper) dump(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		d.buf.WriteString("nil")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface,
+		reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			d.buf.WriteString("nil")
+			return
+		}
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		d.buf.WriteString("time.Time(" + t.Format(time.RFC3339Nano) + ")")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		d.dump(v.Elem(), depth)
+	case reflect.Ptr:
+		r := ref{ptr: v.Pointer(), typ: v.Type()}
+		if d.visited[r] {
+			d.buf.WriteString("<cycle>")
+			return
+		}
+
+		d.visited[r] = true
+		d.buf.WriteString("&")
+		d.dump(v.Elem(), depth)
+		delete(d.visited, r)
+	case reflect.Struct:
+		d.dumpStruct(v, depth)
+	case reflect.Map:
+		d.dumpMap(v, depth)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			r := ref{ptr: v.Pointer(), typ: v.Type()}
+			if d.visited[r] && v.Len() > 0 {
+				d.buf.WriteString("<cycle>")
+				return
+			}
+			d.visited[r] = true
+			defer delete(d.visited, r)
+		}
+
+		d.buf.WriteString(v.Type().String() + "{")
+		if v.Len() == 0 {
+			d.buf.WriteString("}")
+			return
+		}
+
+		d.buf.WriteString("\n")
+		for i := 0; i < v.Len(); i++ {
+			d.indent(depth + 1)
+			d.dump(v.Index(i), depth+1)
+			d.buf.WriteString(",\n")
+		}
+		d.indent(depth)
+		d.buf.WriteString("}")
+	case reflect.String:
+		d.buf.WriteString(strconv.Quote(v.String()))
+	case reflect.Bool:
+		d.buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		d.buf.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	case reflect.Complex64, reflect.Complex128:
+		d.buf.WriteString(strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()))
+	default:
+		// functions, channels and unsafe pointers have no deterministic
+		// representation, so only print their type
+		d.buf.WriteString(v.Type().String())
+	}
+}
+
+func (d *dumper) dumpStruct(v reflect.Value, depth int) {
+	t := v.Type()
+
+	d.buf.WriteString(t.String() + "{")
+
+	wrote := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// we can't access the value of unexported fields
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if field.Tag.Get(d.tagName) == "-" || field.Tag.Get("dump") == "-" {
+			continue
+		}
+
+		if !wrote {
+			d.buf.WriteString("\n")
+			wrote = true
+		}
+
+		d.indent(depth + 1)
+		d.buf.WriteString(field.Name + ": ")
+
+		if field.Tag.Get("dump") == "redact" {
+			d.buf.WriteString("<redacted>")
+		} else {
+			d.dump(v.Field(i), depth+1)
+		}
+		d.buf.WriteString(",\n")
+	}
+
+	if wrote {
+		d.indent(depth)
+	}
+	d.buf.WriteString("}")
+}
+
+func (d *dumper) dumpMap(v reflect.Value, depth int) {
+	r := ref{ptr: v.Pointer(), typ: v.Type()}
+	if d.visited[r] {
+		d.buf.WriteString("<cycle>")
+		return
+	}
+	d.visited[r] = true
+	defer delete(d.visited, r)
+
+	d.buf.WriteString(v.Type().String() + "{")
+	if v.Len() == 0 {
+		d.buf.WriteString("}")
+		return
+	}
+
+	entries := make(mapEntries, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		entries = append(entries, mapEntry{
+			key:   d.sub(k, depth+1),
+			order: d.sub(v.MapIndex(k), depth+1),
+		})
+	}
+	sort.Sort(entries)
+
+	d.buf.WriteString("\n")
+	for _, e := range entries {
+		d.indent(depth + 1)
+		d.buf.WriteString(e.key + ": " + e.order + ",\n")
+	}
+	d.indent(depth)
+	d.buf.WriteString("}")
+}
+
+// sub returns the representation of v as a separate string.
+func (d *dumper) sub(v reflect.Value, depth int) string {
+	buf := d.buf
+	defer func() { d.buf = buf }()
+
+	d.buf = new(bytes.Buffer)
+	d.dump(v, depth)
+	return d.buf.String()
+}
+
+func (d *dumper) indent(depth int) {
+	d.buf.WriteString(strings.Repeat("  ", depth))
+}
+
+// mapEntry is a single map entry with the textual form of its key. order is
+// the textual form of its value, which sorts entries whose keys have the same
+// textual form, such as pointers to equal values.
+type mapEntry struct {
+	key   string
+	order string
+}
+
+// mapEntries sorts map entries by their key and order.
+type mapEntries []mapEntry
+
+func (m mapEntries) Len() int      { return len(m) }
+func (m mapEntries) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+
+func (m mapEntries) Less(i, j int) bool {
+	if m[i].key != m[j].key {
+		return m[i].key < m[j].key
+	}
+	return m[i].order < m[j].order
+}
@@ -0,0 +1,180 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type dumpNode struct {
+	Name     string
+	Parent   *dumpNode
+	Children []*dumpNode
+}
+
+func TestDump(t *testing.T) {
+	type Inner struct {
+		A int
+		B []string
+	}
+
+	type T struct {
+		Name     string
+		Score    float64
+		Inner    Inner
+		Ptr      *Inner
+		Nil      *Inner
+		Labels   map[string]int
+		Empty    []int
+		Any      interface{}
+		At       time.Time
+		Ignored  string `structs:"-"`
+		Skipped  string `dump:"-"`
+		Password string `dump:"redact"`
+		hidden   string
+	}
+
+	v := T{
+		Name:     "gopher",
+		Score:    1.5,
+		Inner:    Inner{A: 1, B: []string{"x", "y"}},
+		Ptr:      &Inner{A: 2},
+		Labels:   map[string]int{"b": 2, "a": 1, "c": 3},
+		Empty:    []int{},
+		Any:      42,
+		At:       time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC),
+		Ignored:  "ignored",
+		Skipped:  "skipped",
+		Password: "secret",
+		hidden:   "hidden",
+	}
+
+	expected := `structs.T{
+  Name: "gopher",
+  Score: 1.5,
+  Inner: structs.Inner{
+    A: 1,
+    B: []string{
+      "x",
+      "y",
+    },
+  },
+  Ptr: &structs.Inner{
+    A: 2,
+    B: nil,
+  },
+  Nil: nil,
+  Labels: map[string]int{
+    "a": 1,
+    "b": 2,
+    "c": 3,
+  },
+  Empty: []int{},
+  Any: 42,
+  At: time.Time(2018-10-10T00:00:00Z),
+  Password: <redacted>,
+}`
+
+	if out := Dump(v); out != expected {
+		t.Errorf("Dump should return\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestDump_Deterministic(t *testing.T) {
+	m := make(map[int]string)
+	for i := 0; i < 100; i++ {
+		m[i] = "value"
+	}
+
+	first := Dump(m)
+	for i := 0; i < 10; i++ {
+		if out := Dump(m); out != first {
+			t.Fatalf("Dump should be deterministic, got:\n%s\nand:\n%s", first, out)
+		}
+	}
+}
+
+func TestDump_EqualPointerKeys(t *testing.T) {
+	a, b := 1, 1
+	m := map[*int]string{&a: "a", &b: "b"}
+
+	expected := "map[*int]string{\n  &1: \"a\",\n  &1: \"b\",\n}"
+	for i := 0; i < 50; i++ {
+		if out := Dump(m); out != expected {
+			t.Fatalf("Dump should sort equal keys by their values, got:\n%s", out)
+		}
+	}
+}
+
+func TestDump_Cycle(t *testing.T) {
+	parent := &dumpNode{Name: "parent"}
+	child := &dumpNode{Name: "child", Parent: parent}
+	parent.Children = []*dumpNode{child}
+
+	expected := `&structs.dumpNode{
+  Name: "parent",
+  Parent: nil,
+  Children: []*structs.dumpNode{
+    &structs.dumpNode{
+      Name: "child",
+      Parent: <cycle>,
+      Children: nil,
+    },
+  },
+}`
+
+	if out := Dump(parent); out != expected {
+		t.Errorf("Dump should return\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestDump_FirstFieldPointer(t *testing.T) {
+	type Inner struct {
+		A int
+	}
+
+	type Middle struct {
+		In Inner
+		Q  *Inner
+	}
+
+	// Q points to the first field of m, so it has the same address as m
+	m := &Middle{In: Inner{A: 1}}
+	m.Q = &m.In
+
+	expected := `&structs.Middle{
+  In: structs.Inner{
+    A: 1,
+  },
+  Q: &structs.Inner{
+    A: 1,
+  },
+}`
+
+	if out := Dump(m); out != expected {
+		t.Errorf("Dump should return\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestDump_Complex(t *testing.T) {
+	type T struct {
+		A complex128
+		B complex64
+	}
+
+	expected := "structs.T{\n  A: (1-2i),\n  B: (0.5+1i),\n}"
+	if out := Dump(T{A: complex(1, -2), B: complex(0.5, 1)}); out != expected {
+		t.Errorf("Dump should return\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestFdump(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Fdump(&buf, struct{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "struct {}{}" {
+		t.Errorf("Fdump should write %q, got: %q", "struct {}{}", buf.String())
+	}
+}
@@ -0,0 +1,253 @@
+package structs
+
+import (
+	"math"
+	"reflect"
+)
+
+// EqualOptions configures the comparison of Equal.
+type EqualOptions struct {
+	// TagName is the struct tag used to ignore fields. It defaults to
+	// DefaultTagName.
+	TagName string
+
+	// Comparers contains custom comparison functions for specific types.
+	// They take precedence over every other rule. Example:
+	//
+	//   opts := &structs.EqualOptions{
+	//       Comparers: map[reflect.Type]func(a, b interface{}) bool{
+	//           reflect.TypeOf(time.Time{}): func(a, b interface{}) bool {
+	//               return a.(time.Time).Equal(b.(time.Time))
+	//           },
+	//       },
+	//   }
+	Comparers map[reflect.Type]func(a, b interface{}) bool
+
+	// FloatTolerance is the maximum absolute difference for two floats to be
+	// considered equal.
+	FloatTolerance float64
+
+	// NaNsEqual treats two NaN floats as equal.
+	NaNsEqual bool
+
+	// NilEqualsEmpty treats nil slices and maps as equal to empty ones.
+	NilEqualsEmpty bool
+}
+
+// Equal reports whether a and b are semantically equal. Unlike
+// reflect.DeepEqual only the exported fields of structs are compared and
+// pointers are compared by the values they point to, so a struct and a
+// pointer to an equal struct are equal. If opts is nil the default options
+// are used.
+//
+// A struct tag with the content of "-" ignores that particular field. Example:
+//
+//   // Field is not compared
+//   Field string `structs:"-"`
+//   Field string `eq:"-"`
+//
+// Types with an "Equal(T) bool" method, such as time.Time, are compared with
+// that method. Structs without any exported fields are compared with
+// reflect.DeepEqual.
+func Equal(a, b interface{}, opts *EqualOptions) bool {
+	if opts == nil {
+		opts = &EqualOptions{}
+	}
+
+	e := &equaler{
+		opts:    opts,
+		tagName: opts.TagName,
+		visited: make(map[visit]bool),
+	}
+
+	if e.tagName == "" {
+		e.tagName = DefaultTagName
+	}
+
+	return e.equal(indirect(reflect.ValueOf(a)), indirect(reflect.ValueOf(b)))
+}
+
+// indirect returns the value that v points to, following all pointers until
+// a non pointer or a nil pointer.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// visit is a pair of pointers which are currently being compared, so we can
+// detect cycles.
+type visit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+type equaler struct {
+	opts    *EqualOptions
+	tagName string
+	visited map[visit]bool
+}
+
+func (e *equaler) equal(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	t := a.Type()
+
+	if fn, ok := e.opts.Comparers[t]; ok && a.CanInterface() && b.CanInterface() {
+		return fn(a.Interface(), b.Interface())
+	}
+
+	if ok, eq := equalMethod(a, b); ok {
+		return eq
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if a.Kind() != reflect.Ptr && e.opts.NilEqualsEmpty && a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		if a.Pointer() == b.Pointer() && a.Kind() != reflect.Slice {
+			return true
+		}
+
+		v := visit{a: a.Pointer(), b: b.Pointer(), typ: t}
+		if e.visited[v] {
+			return true
+		}
+		e.visited[v] = true
+	}
+
+	switch a.Kind() {
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return e.equal(a.Elem(), b.Elem())
+	case reflect.Ptr:
+		return e.equal(a.Elem(), b.Elem())
+	case reflect.Struct:
+		return e.equalStruct(a, b)
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+
+		for i := 0; i < a.Len(); i++ {
+			if !e.equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+
+		for _, k := range a.MapKeys() {
+			bv := b.MapIndex(k)
+			if !bv.IsValid() || !e.equal(a.MapIndex(k), bv) {
+				return false
+			}
+		}
+		return true
+	case reflect.Float32, reflect.Float64:
+		return e.equalFloat(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return e.equalFloat(real(a.Complex()), real(b.Complex())) &&
+			e.equalFloat(imag(a.Complex()), imag(b.Complex()))
+	case reflect.Func:
+		// functions are only equal if both are nil, like reflect.DeepEqual
+		return a.IsNil() && b.IsNil()
+	}
+
+	if a.CanInterface() && b.CanInterface() {
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+
+	return false
+}
+
+func (e *equaler) equalStruct(a, b reflect.Value) bool {
+	t := a.Type()
+
+	exported := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// we can't access the value of unexported fields
+		if field.PkgPath != "" {
+			continue
+		}
+		exported = true
+
+		if field.Tag.Get(e.tagName) == "-" || field.Tag.Get("eq") == "-" {
+			continue
+		}
+
+		if !e.equal(a.Field(i), b.Field(i)) {
+			return false
+		}
+	}
+
+	// do not treat structs without exported fields as always equal, ie:
+	// time.Time
+	if !exported && a.CanInterface() && b.CanInterface() {
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+
+	return true
+}
+
+func (e *equaler) equalFloat(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return e.opts.NaNsEqual && math.IsNaN(a) && math.IsNaN(b)
+	}
+
+	if a == b {
+		return true
+	}
+
+	return math.Abs(a-b) <= e.opts.FloatTolerance
+}
+
+// equalMethod compares a and b with their "Equal(T) bool" method. The first
+// returned boolean reports whether the type has such a method.
+func equalMethod(a, b reflect.Value) (bool, bool) {
+	// methods of interface types are resolved on their dynamic values
+	if a.Kind() == reflect.Interface || !a.CanInterface() || !b.CanInterface() {
+		return false, false
+	}
+
+	m, ok := a.Type().MethodByName("Equal")
+	if !ok {
+		return false, false
+	}
+
+	mt := m.Type
+	if mt.NumIn() != 2 || mt.In(1) != a.Type() ||
+		mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		// don't call the method on nil values, they may not handle it
+		if a.IsNil() || b.IsNil() {
+			return false, false
+		}
+	}
+
+	return true, m.Func.Call([]reflect.Value{a, b})[0].Bool()
+}
@@ -0,0 +1,162 @@
+package structs
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type equalNode struct {
+	Name   string
+	Parent *equalNode
+}
+
+func TestEqual(t *testing.T) {
+	type Inner struct {
+		A int
+	}
+
+	type T struct {
+		Name    string
+		Inner   *Inner
+		Tags    []string
+		Labels  map[string]interface{}
+		Ignored string `eq:"-"`
+		Omitted string `structs:"-"`
+		hidden  string
+	}
+
+	a := T{
+		Name:    "gopher",
+		Inner:   &Inner{A: 1},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]interface{}{"x": 1},
+		Ignored: "a",
+		Omitted: "a",
+		hidden:  "a",
+	}
+
+	b := T{
+		Name:    "gopher",
+		Inner:   &Inner{A: 1},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]interface{}{"x": 1},
+		Ignored: "b",
+		Omitted: "b",
+		hidden:  "b",
+	}
+
+	if !Equal(a, &b, nil) {
+		t.Error("Equal should ignore unexported and ignored fields")
+	}
+
+	b.Inner.A = 2
+	if Equal(a, b, nil) {
+		t.Error("Equal should compare the values of pointers")
+	}
+
+	b.Inner.A = 1
+	b.Labels["x"] = 2
+	if Equal(a, b, nil) {
+		t.Error("Equal should compare map values")
+	}
+}
+
+func TestEqual_Time(t *testing.T) {
+	type T struct {
+		At time.Time
+	}
+
+	at := time.Date(2018, 10, 10, 12, 0, 0, 0, time.UTC)
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	if !Equal(T{At: at}, T{At: at.In(loc)}, nil) {
+		t.Error("Equal should use the Equal method of time.Time")
+	}
+
+	if Equal(T{At: at}, T{At: at.Add(time.Second)}, nil) {
+		t.Error("Equal should detect different times")
+	}
+}
+
+func TestEqual_Comparers(t *testing.T) {
+	type T struct {
+		Name string
+	}
+
+	opts := &EqualOptions{
+		Comparers: map[reflect.Type]func(a, b interface{}) bool{
+			reflect.TypeOf(""): func(a, b interface{}) bool {
+				return len(a.(string)) == len(b.(string))
+			},
+		},
+	}
+
+	if !Equal(T{Name: "abc"}, T{Name: "xyz"}, opts) {
+		t.Error("Equal should use the custom comparer")
+	}
+}
+
+func TestEqual_Floats(t *testing.T) {
+	type T struct {
+		F float64
+	}
+
+	x, y := 0.1, 0.2
+	if Equal(T{F: 0.3}, T{F: x + y}, nil) {
+		t.Error("Equal should compare floats exactly by default")
+	}
+
+	if !Equal(T{F: 0.3}, T{F: x + y}, &EqualOptions{FloatTolerance: 1e-9}) {
+		t.Error("Equal should compare floats with the given tolerance")
+	}
+
+	nan := T{F: math.NaN()}
+	if Equal(nan, nan, nil) {
+		t.Error("Equal should not treat NaNs as equal by default")
+	}
+
+	if !Equal(nan, nan, &EqualOptions{NaNsEqual: true}) {
+		t.Error("Equal should treat NaNs as equal with NaNsEqual")
+	}
+}
+
+func TestEqual_NilEqualsEmpty(t *testing.T) {
+	type T struct {
+		S []int
+		M map[string]int
+	}
+
+	a := T{}
+	b := T{S: []int{}, M: map[string]int{}}
+
+	if Equal(a, b, nil) {
+		t.Error("Equal should not treat nil and empty as equal by default")
+	}
+
+	if !Equal(a, b, &EqualOptions{NilEqualsEmpty: true}) {
+		t.Error("Equal should treat nil and empty as equal with NilEqualsEmpty")
+	}
+}
+
+func TestEqual_Cycle(t *testing.T) {
+	a := &equalNode{Name: "node"}
+	a.Parent = a
+
+	b := &equalNode{Name: "node"}
+	b.Parent = b
+
+	if !Equal(a, b, nil) {
+		t.Error("Equal should handle cycles")
+	}
+}
+
+func TestEqual_DifferentTypes(t *testing.T) {
+	type A struct{ X int }
+	type B struct{ X int }
+
+	if Equal(A{X: 1}, B{X: 1}, nil) {
+		t.Error("Equal should return false for different types")
+	}
+}
@@ -9,6 +9,8 @@ import (
 var (
 	errNotExported = errors.New("field is not exported")
 	errNotSettable = errors.New("field is not settable")
+
+	errNotAddressable = errors.New("field is not addressable, pass a pointer to New")
 )
 
 // Field represents a single struct field that encapsulates high level
@@ -17,6 +19,10 @@ type Field struct {
 	value      reflect.Value
 	field      reflect.StructField
 	defaultTag string
+
+	// unexported allows reading the value of unexported fields, see
+	// Struct.IncludeUnexported.
+	unexported bool
 }
 
 // Tag returns the value associated with key in the tag string. If there is no
@@ -26,8 +32,14 @@ func (f *Field) Tag(key string) string {
 }
 
 // Value returns the underlying value of the field. It panics if the field
-// is not exported.
+// is not exported, unless it was retrieved from a Struct with
+// IncludeUnexported enabled. In that case a read-only copy of the value is
+// returned.
 func (f *Field) Value() interface{} {
+	if f.unexported {
+		return readValue(f.value)
+	}
+
 	return f.value.Interface()
 }
 
@@ -42,12 +54,16 @@ func (f *Field) IsExported() bool {
 }
 
 // IsZero returns true if the given field is not initialized (has a zero value).
-// It panics if the field is not exported.
+// Types implementing an "IsZero() bool" method, such as time.Time, or with a
+// function registered via RegisterZeroFunc are checked with it. It panics if
+// the field is not exported, unless it was retrieved from a Struct with
+// IncludeUnexported enabled.
 func (f *Field) IsZero() bool {
-	zero := reflect.Zero(f.value.Type()).Interface()
-	current := f.Value()
+	if !f.IsExported() && !f.unexported {
+		panic(errNotExported)
+	}
 
-	return reflect.DeepEqual(current, zero)
+	return isZero(f.value)
 }
 
 // Name returns the name of the given field
@@ -84,6 +100,23 @@ func (f *Field) Set(val interface{}) error {
 	return nil
 }
 
+// Addr returns a pointer to the field, such as &s.Name, which can be passed to
+// functions like sql.Rows.Scan or json.Unmarshal. It returns an error if the
+// field is not exported, is reached through an unexported field or is not
+// addressable, which is the case if the struct wasn't passed to New as a
+// pointer.
+func (f *Field) Addr() (interface{}, error) {
+	if !f.IsExported() || !f.value.CanInterface() {
+		return nil, errNotExported
+	}
+
+	if !f.value.CanAddr() {
+		return nil, errNotAddressable
+	}
+
+	return f.value.Addr().Interface(), nil
+}
+
 // Zero sets the field to its zero value. It returns an error if the field is not
 // settable (not addressable or not exported).
 func (f *Field) Zero() error {
@@ -98,9 +131,11 @@ func (f *Field) Zero() error {
 //   // Field is ignored by this package.
 //   Field *http.Request `structs:"-"`
 //
-// It panics if field is not exported or if field's kind is not struct
+// Unexported fields are skipped, unless the field was retrieved from a Struct
+// with IncludeUnexported enabled. It panics if field is not exported or if
+// field's kind is not struct
 func (f *Field) Fields() []*Field {
-	return getFields(f.value, f.defaultTag)
+	return getFields(f.value, f.defaultTag, f.unexported)
 }
 
 // Field returns the field from a nested struct. It panics if the nested struct
@@ -126,7 +161,7 @@ func (f *Field) FieldOk(name string) (*Field, bool) {
 		a := f.value.Addr()
 		value = &a
 	}
-	v := strctVal(value.Interface())
+	v := strctValue(*value)
 	t := v.Type()
 
 	field, ok := t.FieldByName(name)
@@ -135,7 +170,9 @@ func (f *Field) FieldOk(name string) (*Field, bool) {
 	}
 
 	return &Field{
-		field: field,
-		value: v.FieldByName(name),
+		field:      field,
+		value:      v.FieldByName(name),
+		defaultTag: f.defaultTag,
+		unexported: f.unexported,
 	}, true
 }
@@ -368,11 +368,49 @@ func TestField_Fields(t *testing.T) {
 	s := newStruct()
 	fields := s.Field("Bar").Fields()
 
-	if len(fields) != 3 {
+	// the unexported field g is skipped
+	if len(fields) != 2 {
+		t.Errorf("We expect 2 fields in embedded struct, was: %d", len(fields))
+	}
+
+	s.IncludeUnexported = true
+	if fields := s.Field("Bar").Fields(); len(fields) != 3 {
 		t.Errorf("We expect 3 fields in embedded struct, was: %d", len(fields))
 	}
 }
 
+func TestField_Fields_TagName(t *testing.T) {
+	type C struct {
+		Visible string
+		Hidden  string `json:"-"`
+	}
+
+	type B struct {
+		C      C
+		Hidden string `json:"-"`
+	}
+
+	type A struct {
+		B B
+	}
+
+	s := New(&A{})
+	s.TagName = "json"
+
+	b := s.Fields()[0]
+	if fields := b.Fields(); len(fields) != 1 {
+		t.Errorf("We expect 1 field in B, was: %d", len(fields))
+	}
+
+	// fields returned by Fields and FieldOk of a nested field
+	for _, c := range []*Field{b.Fields()[0], b.Field("C")} {
+		fields := c.Fields()
+		if len(fields) != 1 || fields[0].Name() != "Visible" {
+			t.Errorf("We expect only the field Visible in C, was: %d fields", len(fields))
+		}
+	}
+}
+
 func TestField_FieldOk(t *testing.T) {
 	s := newStruct()
 
@@ -395,3 +433,49 @@ func TestField_FieldOk(t *testing.T) {
 		t.Errorf("The value of 'e' should be 'example, got: %s", val)
 	}
 }
+
+func TestField_Addr(t *testing.T) {
+	s := newStruct()
+
+	p, err := s.Field("A").Addr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	*p.(*string) = "changed"
+	if v := s.Field("A").Value(); v != "changed" {
+		t.Errorf("Addr should return a pointer to the field, got: %v", v)
+	}
+
+	p, err = s.Field("Bar").Field("E").Addr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *p.(*string) != "example" {
+		t.Errorf("Addr of a nested field should point to example, got: %s", *p.(*string))
+	}
+
+	if _, err := s.Field("d").Addr(); err != errNotExported {
+		t.Errorf("Addr should return an error for an unexported field, got: %v", err)
+	}
+
+	if _, err := New(Baz{}).Field("A").Addr(); err != errNotAddressable {
+		t.Errorf("Addr should return an error for a non pointer struct, got: %v", err)
+	}
+
+	type Inner struct {
+		X int
+	}
+
+	type T struct {
+		inner Inner
+	}
+
+	u := New(&T{})
+	u.IncludeUnexported = true
+
+	if _, err := u.Field("inner").Field("X").Addr(); err != errNotExported {
+		t.Errorf("Addr should return an error for a field of an unexported field, got: %v", err)
+	}
+}
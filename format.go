@@ -0,0 +1,101 @@
+package structs
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// formatOption returns v formatted according to the "format" tag option. The
+// format is one of:
+//
+//   - a fmt verb, such as "%.2f" or "%08d", applied to any value
+//   - "hex" or "base64", applied to byte slices, byte arrays and strings
+//   - a time layout, such as "2006-01-02", applied to time.Time values
+//
+// A nil pointer is returned as nil. An error is returned if the format can't
+// be applied to the type of v.
+func formatOption(v reflect.Value, format string) (interface{}, error) {
+	v = readable(v)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if strings.HasPrefix(format, "%") {
+		if !v.CanInterface() {
+			return nil, fmt.Errorf("can't format %s with %q", v.Type(), format)
+		}
+
+		// check the verb with the zero value, as the value itself may
+		// contain "%!"
+		zero := reflect.Zero(v.Type()).Interface()
+		if strings.Contains(fmt.Sprintf(format, zero), "%!") {
+			return nil, fmt.Errorf("invalid format %q for %s", format, v.Type())
+		}
+
+		return fmt.Sprintf(format, v.Interface()), nil
+	}
+
+	switch format {
+	case "hex", "base64":
+		var b []byte
+		switch {
+		case v.Kind() == reflect.String:
+			b = []byte(v.String())
+		case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) &&
+			v.Type().Elem().Kind() == reflect.Uint8:
+			b = make([]byte, v.Len())
+			for i := range b {
+				b[i] = byte(v.Index(i).Uint())
+			}
+		default:
+			return nil, fmt.Errorf("invalid format %q for %s", format, v.Type())
+		}
+
+		if format == "hex" {
+			return hex.EncodeToString(b), nil
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(format), nil
+	}
+
+	return nil, fmt.Errorf("invalid format %q for %s", format, v.Type())
+}
+
+// stringOption returns the value for the "string" tag option, which is the
+// output of the String method of v. The method is looked up in the method
+// sets of both v and its pointer. If v doesn't implement fmt.Stringer, the
+// output of fmt.Sprint is returned instead, or an error if strict is true. A
+// nil pointer is returned as nil.
+func stringOption(v reflect.Value, strict bool) (interface{}, error) {
+	v = readable(v)
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if s, ok := implementer(v, stringerType); ok {
+		return s.(fmt.Stringer).String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if strict {
+		return nil, fmt.Errorf("%s does not implement fmt.Stringer", v.Type())
+	}
+
+	return fmt.Sprint(readValue(v)), nil
+}
@@ -0,0 +1,95 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMap_FormatOption(t *testing.T) {
+	type T struct {
+		Created time.Time  `structs:"created,format=2006-01-02"`
+		Updated *time.Time `structs:"updated,format=2006-01-02"`
+		Price   float64    `structs:"price,format=%.2f"`
+		ID      int        `structs:"id,format=%04d"`
+		Hash    []byte     `structs:"hash,format=hex"`
+		Key     [2]byte    `structs:"key,format=base64"`
+		Empty   string     `structs:"empty,omitempty,format=%q"`
+	}
+
+	v := T{
+		Created: time.Date(2018, 10, 10, 12, 0, 0, 0, time.UTC),
+		Price:   3.14159,
+		ID:      7,
+		Hash:    []byte{0xca, 0xfe},
+		Key:     [2]byte{'h', 'i'},
+	}
+
+	m, err := New(v).MapErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"created": "2018-10-10",
+		"updated": nil,
+		"price":   "3.14",
+		"id":      "0007",
+		"hash":    "cafe",
+		"key":     "aGk=",
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values, err := New(v).ValuesErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedValues := []interface{}{"2018-10-10", nil, "3.14", "0007", "cafe", "aGk="}
+	if !reflect.DeepEqual(values, expectedValues) {
+		t.Errorf("Values should return %v, got: %v", expectedValues, values)
+	}
+}
+
+func TestMap_InvalidFormatOption(t *testing.T) {
+	type T struct {
+		Name  string `structs:"name"`
+		Count int    `structs:"count,format=hex"`
+		Price int    `structs:"price,format=%s"`
+	}
+
+	m, err := New(T{Name: "gopher", Count: 1}).MapErr()
+	if err == nil {
+		t.Error("MapErr should return an error for an invalid format")
+	}
+
+	if _, ok := m["count"]; ok {
+		t.Error("Map should not include a field with an invalid format")
+	}
+
+	if m["name"] != "gopher" {
+		t.Errorf("Map should still include the other fields, got: %v", m)
+	}
+
+	if _, err := New(T{}).ValuesErr(); err == nil {
+		t.Error("ValuesErr should return an error for an invalid format")
+	}
+}
+
+func TestMap_FormatOption_PercentInValue(t *testing.T) {
+	type T struct {
+		S string `structs:"s,format=%s"`
+	}
+
+	m, err := New(T{S: "100%!"}).MapErr()
+	if err != nil {
+		t.Fatalf("MapErr should not return an error for a value containing %%!, got: %v", err)
+	}
+
+	if m["s"] != "100%!" {
+		t.Errorf("Map should return 100%%!, got: %v", m["s"])
+	}
+}
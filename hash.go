@@ -0,0 +1,269 @@
+package structs
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var (
+	errCycle = errors.New("value contains a cycle")
+
+	binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+)
+
+// Hash returns a 64-bit FNV-1a hash of the given value. For more info refer to
+// Digest.
+func Hash(v interface{}) (uint64, error) {
+	h := fnv.New64a()
+	if err := Digest(v, h); err != nil {
+		return 0, err
+	}
+
+	return h.Sum64(), nil
+}
+
+// Digest writes a deterministic binary representation of v to the given hash.
+// Exported struct fields are written in declaration order together with their
+// names and the type of the struct, and map entries are sorted, so equal
+// values always produce the same digest, regardless of the process or the Go
+// version. Pointers are followed, a nil pointer is different from a pointer to
+// a zero value. Passing a pointer to a value results in the same digest as
+// passing the value itself.
+//
+// A struct tag with the content of "-" ignores that particular field. Example:
+//
+//   // Field is not part of the digest
+//   Field string `structs:"-"`
+//   Field string `hash:"-"`
+//
+// time.Time values are hashed by their instant, so the same time in
+// different locations results in the same digest. Types implementing
+// encoding.BinaryMarshaler or encoding.TextMarshaler are hashed with the
+// output of their methods. An error is returned for values containing cycles,
+// functions, channels or unsafe pointers.
+func Digest(v interface{}, h hash.Hash) error {
+	d := &digester{
+		w:       h,
+		tagName: DefaultTagName,
+		visited: make(map[ref]bool),
+	}
+
+	// like New, a pointer to a value has the same digest as the value itself
+	return d.digest(indirect(reflect.ValueOf(v)))
+}
+
+type digester struct {
+	w       io.Writer
+	tagName string
+
+	// visited contains the pointers of the values which are currently being
+	// written, so we can detect cycles.
+	visited map[ref]bool
+}
+
+func (d *digester) digest(v reflect.Value) error {
+	if !v.IsValid() {
+		d.writeString("nil")
+		return nil
+	}
+
+	t := v.Type()
+	d.writeString(t.String())
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			d.writeUint(0)
+			return nil
+		}
+		d.writeUint(1)
+	}
+
+	if t == timeType {
+		tm := v.Interface().(time.Time)
+		d.writeUint(uint64(tm.Unix()))
+		d.writeUint(uint64(tm.Nanosecond()))
+		return nil
+	}
+
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface && v.CanInterface() {
+		if ok, err := d.marshal(v); ok {
+			return err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		return d.digest(v.Elem())
+	case reflect.Ptr:
+		r := ref{ptr: v.Pointer(), typ: v.Type()}
+		if d.visited[r] {
+			return errCycle
+		}
+
+		d.visited[r] = true
+		defer delete(d.visited, r)
+		return d.digest(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			// we can't access the value of unexported fields
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if field.Tag.Get(d.tagName) == "-" || field.Tag.Get("hash") == "-" {
+				continue
+			}
+
+			d.writeString(field.Name)
+			if err := d.digest(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return d.digestMap(v)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Len() > 0 {
+			r := ref{ptr: v.Pointer(), typ: v.Type()}
+			if d.visited[r] {
+				return errCycle
+			}
+			d.visited[r] = true
+			defer delete(d.visited, r)
+		}
+
+		d.writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := d.digest(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		d.writeString(v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			d.writeUint(1)
+		} else {
+			d.writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		d.writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		d.writeUint(math.Float64bits(real(v.Complex())))
+		d.writeUint(math.Float64bits(imag(v.Complex())))
+	default:
+		return fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+
+	return nil
+}
+
+// digestMap writes the map entries sorted by the digest of their keys and,
+// for distinct keys with the same digest, such as pointers to equal values,
+// by the digest of their values.
+func (d *digester) digestMap(v reflect.Value) error {
+	r := ref{ptr: v.Pointer(), typ: v.Type()}
+	if d.visited[r] {
+		return errCycle
+	}
+	d.visited[r] = true
+	defer delete(d.visited, r)
+
+	entries := make(mapEntries, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		key, err := d.sub(k)
+		if err != nil {
+			return err
+		}
+
+		val, err := d.sub(v.MapIndex(k))
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, mapEntry{key: string(key), order: string(val)})
+	}
+	sort.Sort(entries)
+
+	d.writeUint(uint64(len(entries)))
+	for _, e := range entries {
+		d.w.Write([]byte(e.key))
+		d.w.Write([]byte(e.order))
+	}
+
+	return nil
+}
+
+// marshal writes the output of the BinaryMarshaler or TextMarshaler
+// implementation of v. The returned boolean reports whether v implements one
+// of them. Methods with a pointer receiver are called on a copy of v if it's
+// not addressable, so v and a pointer to v result in the same digest.
+func (d *digester) marshal(v reflect.Value) (bool, error) {
+	x := v.Interface()
+	if pt := reflect.PtrTo(v.Type()); pt.Implements(binaryMarshalerType) || pt.Implements(textMarshalerType) {
+		if !v.CanAddr() {
+			c := reflect.New(v.Type()).Elem()
+			c.Set(v)
+			v = c
+		}
+		x = v.Addr().Interface()
+	}
+
+	var b []byte
+	var err error
+
+	switch m := x.(type) {
+	case encoding.BinaryMarshaler:
+		b, err = m.MarshalBinary()
+	case encoding.TextMarshaler:
+		b, err = m.MarshalText()
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, err
+	}
+
+	d.writeString(string(b))
+	return true, nil
+}
+
+// sub returns the digest of v as a separate byte slice.
+func (d *digester) sub(v reflect.Value) ([]byte, error) {
+	w := d.w
+	defer func() { d.w = w }()
+
+	var buf bytes.Buffer
+	d.w = &buf
+	err := d.digest(v)
+	return buf.Bytes(), err
+}
+
+func (d *digester) writeUint(n uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], n)
+	d.w.Write(b[:])
+}
+
+// writeString writes s prefixed with its length, so adjacent strings can't
+// produce the same output.
+func (d *digester) writeString(s string) {
+	d.writeUint(uint64(len(s)))
+	io.WriteString(d.w, s)
+}
@@ -0,0 +1,236 @@
+package structs
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+)
+
+type hashNode struct {
+	Name   string
+	Parent *hashNode
+}
+
+func TestHash(t *testing.T) {
+	type T struct {
+		A string
+		B int
+		C map[string]int
+		D []*int
+		E interface{}
+		F string `hash:"-"`
+		G string `structs:"-"`
+		h string
+	}
+
+	one := 1
+	a := T{
+		A: "a",
+		B: 1,
+		C: map[string]int{"x": 1, "y": 2, "z": 3},
+		D: []*int{&one, nil},
+		E: 1.5,
+		F: "ignored",
+		G: "ignored",
+		h: "ignored",
+	}
+
+	b := a
+	b.C = map[string]int{"z": 3, "y": 2, "x": 1}
+	b.D = []*int{new(int), nil}
+	*b.D[0] = 1
+	b.F = "other"
+	b.G = "other"
+	b.h = "other"
+
+	ha, err := Hash(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hb, err := Hash(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ha != hb {
+		t.Errorf("Hash should be equal for equal values, got: %d and %d", ha, hb)
+	}
+
+	b.E = 2.5
+	if hb, _ = Hash(b); ha == hb {
+		t.Error("Hash should differ for different values")
+	}
+}
+
+func TestHash_Stable(t *testing.T) {
+	type T struct {
+		A string
+		B int
+	}
+
+	// the hash must not change between releases, as it's used for cache keys
+	h, err := Hash(T{A: "gopher", B: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h != 15315157883837287693 {
+		t.Errorf("Hash should be stable, got: %d", h)
+	}
+
+	if h3, _ := Hash(struct {
+		A string
+		B int
+	}{A: "gopher", B: 42}); h == h3 {
+		t.Error("Hash should include the type information")
+	}
+}
+
+func TestHash_NilAndZero(t *testing.T) {
+	type T struct {
+		P *int
+		S []int
+	}
+
+	nilHash, _ := Hash(T{})
+	zeroHash, _ := Hash(T{P: new(int), S: []int{}})
+
+	if nilHash == zeroHash {
+		t.Error("Hash should differ for nil and zero values")
+	}
+}
+
+func TestHash_Time(t *testing.T) {
+	type T struct {
+		At time.Time
+	}
+
+	at := time.Date(2018, 10, 10, 12, 0, 0, 0, time.UTC)
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	a, _ := Hash(T{At: at})
+	b, _ := Hash(T{At: at.In(loc)})
+
+	if a != b {
+		t.Errorf("Hash should be equal for the same instant, got: %d and %d", a, b)
+	}
+}
+
+func TestHash_Cycle(t *testing.T) {
+	n := &hashNode{Name: "node"}
+	n.Parent = n
+
+	if _, err := Hash(n); err != errCycle {
+		t.Errorf("Hash should return %v, got: %v", errCycle, err)
+	}
+}
+
+func TestHash_FirstFieldPointer(t *testing.T) {
+	type Inner struct {
+		A int
+	}
+
+	type Middle struct {
+		In Inner
+		Q  *Inner
+	}
+
+	type Outer struct {
+		P *Middle
+	}
+
+	// Q points to the first field of m, so it has the same address as m
+	m := &Middle{In: Inner{A: 1}}
+	m.Q = &m.In
+
+	h1, err := Hash(Outer{P: m})
+	if err != nil {
+		t.Fatalf("Hash should not return an error for an acyclic value, got: %v", err)
+	}
+
+	h2, err := Hash(Outer{P: &Middle{In: Inner{A: 1}, Q: &Inner{A: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h1 != h2 {
+		t.Errorf("Hash should return the same hash for equal values, got: %d and %d", h1, h2)
+	}
+}
+
+func TestHash_EqualPointerKeys(t *testing.T) {
+	a, b := 1, 1
+	m := map[*int]string{&a: "a", &b: "b"}
+
+	h, err := Hash(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 50; i++ {
+		if h2, _ := Hash(m); h2 != h {
+			t.Fatalf("Hash should not depend on the map iteration order, got: %d and %d", h, h2)
+		}
+	}
+}
+
+type hashText struct {
+	name string
+}
+
+func (h *hashText) MarshalText() ([]byte, error) {
+	return []byte(h.name), nil
+}
+
+func TestHash_PointerMarshaler(t *testing.T) {
+	v := hashText{name: "gopher"}
+
+	h1, err := Hash(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h2, err := Hash(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h1 != h2 {
+		t.Errorf("Hash should return the same hash for a value and a pointer, got: %d and %d", h1, h2)
+	}
+
+	if h3, _ := Hash(hashText{name: "other"}); h1 == h3 {
+		t.Error("Hash should use the pointer receiver MarshalText")
+	}
+}
+
+func TestHash_Unsupported(t *testing.T) {
+	type T struct {
+		F func()
+	}
+
+	if _, err := Hash(T{F: func() {}}); err == nil {
+		t.Error("Hash should return an error for functions")
+	}
+}
+
+func TestDigest(t *testing.T) {
+	type T struct {
+		A string
+	}
+
+	a := sha256.New()
+	if err := Digest(T{A: "a"}, a); err != nil {
+		t.Fatal(err)
+	}
+
+	b := sha256.New()
+	if err := Digest(&T{A: "a"}, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(a.Sum(nil)) != string(b.Sum(nil)) {
+		t.Error("Digest should be equal for equal values")
+	}
+}
@@ -0,0 +1,475 @@
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the output of
+// Map for the given struct, pointer to struct or reflect.Type of a struct.
+// The properties are named the same way as the keys of Map. Example:
+//
+//   type Server struct {
+//       Name    string   `structs:"name" doc:"Name of the server"`
+//       Port    int      `structs:"port" validate:"min=1,max=65535"`
+//       Mode    string   `structs:"mode,omitempty" validate:"oneof=dev prod"`
+//       Address *Address `structs:"address,omitempty"`
+//   }
+//
+// All fields without the "omitempty" option, or with a "required" validation,
+// are listed as required. The "doc" tag is used as the description of a
+// property. The following rules of the "validate" tag are converted:
+//
+//   min, max, gte, lte, gt, lt  bounds of numbers, and the length of strings,
+//                               arrays and maps
+//   len                         exact length of strings, arrays and maps
+//   oneof                       space separated list of allowed values
+//   email, url, uuid            format of strings
+//
+// Named nested struct types are described in "$defs" and referenced by their
+// name, while unnamed struct types are described inline. Fields with the
+// "flatten" option are merged into their parent. time.Time is described as a
+// "date-time" string and types implementing encoding.TextMarshaler as strings.
+// Pointers, slices and maps allow null, as their nil values are nil in Map.
+// An error is returned for fields which can't be described, such as channels
+// and functions, or for invalid validation rules. It panics if the kind is not
+// struct.
+func JSONSchema(v interface{}) ([]byte, error) {
+	ts := Schema(v)
+
+	g := &jsonSchemaGen{
+		tagName: DefaultTagName,
+		root:    ts.Type,
+		defs:    NewOrderedMap(),
+		names:   make(map[reflect.Type]string),
+		used:    make(map[string]bool),
+		seen:    map[reflect.Type]*TypeSchema{ts.Type: ts},
+	}
+
+	out := NewOrderedMap()
+	out.Set("$schema", jsonSchemaDraft)
+
+	if err := g.object(ts, out); err != nil {
+		return nil, err
+	}
+
+	if g.defs.Len() > 0 {
+		out.Set("$defs", g.defs)
+	}
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type jsonSchemaGen struct {
+	tagName string
+	root    reflect.Type
+
+	// defs contains the schemas of the named struct types, names the
+	// names they are stored with and used the names taken so far.
+	defs  *OrderedMap
+	names map[reflect.Type]string
+	used  map[string]bool
+
+	// seen contains the type schemas built so far, see schemaOf.
+	seen map[reflect.Type]*TypeSchema
+}
+
+// object describes the struct ts as an object in out.
+func (g *jsonSchemaGen) object(ts *TypeSchema, out *OrderedMap) error {
+	props := NewOrderedMap()
+	var required []string
+
+	if err := g.properties(ts, props, &required); err != nil {
+		return err
+	}
+
+	out.Set("type", "object")
+	out.Set("properties", props)
+	if len(required) > 0 {
+		out.Set("required", required)
+	}
+
+	return nil
+}
+
+// properties adds the properties of the struct ts to props and the names of
+// the required ones to required.
+func (g *jsonSchemaGen) properties(ts *TypeSchema, props *OrderedMap, required *[]string) error {
+	for _, f := range ts.Fields {
+		if !f.Exported || f.Ignored {
+			continue
+		}
+
+		opts := tagOptions(f.Options)
+
+		if opts.Has("flatten") && f.Nested != nil && elemType(f.Type).Kind() == reflect.Struct {
+			if err := g.properties(f.Nested, props, required); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := f.Name
+		if f.TagName != "" {
+			name = f.TagName
+		}
+
+		schema, err := g.field(f, opts)
+		if err != nil {
+			return fmt.Errorf("field %s: %s", f.Name, err)
+		}
+
+		mustExist, err := validationRules(schema, f.Tags["validate"])
+		if err != nil {
+			return fmt.Errorf("field %s: %s", f.Name, err)
+		}
+
+		props.Set(name, schema)
+		if !opts.Has("omitempty") || mustExist {
+			*required = append(*required, name)
+		}
+	}
+
+	return nil
+}
+
+// field returns the schema of the field f.
+func (g *jsonSchemaGen) field(f *FieldSchema, opts tagOptions) (*OrderedMap, error) {
+	var schema *OrderedMap
+
+	_, formatted := opts.Value("format")
+	if formatted || opts.Has("string") {
+		schema = NewOrderedMap()
+		schema.Set("type", "string")
+		if f.Kind == reflect.Ptr {
+			// a nil pointer is formatted as nil
+			schema = nullable(schema)
+		}
+	} else {
+		var err error
+		if schema, err = g.typeSchema(f.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc := f.Tags["doc"]; doc != "" {
+		schema.Set("description", doc)
+	}
+
+	return schema, nil
+}
+
+// typeSchema returns the schema of the type t. Pointers, slices and maps
+// allow null, as their nil values are converted to nil by Map.
+func (g *jsonSchemaGen) typeSchema(t reflect.Type) (*OrderedMap, error) {
+	schema, err := g.nonNullSchema(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return nullable(schema), nil
+	}
+
+	return schema, nil
+}
+
+// nonNullSchema returns the schema of the type t, without allowing null for
+// nil values of t.
+func (g *jsonSchemaGen) nonNullSchema(t reflect.Type) (*OrderedMap, error) {
+	t = elemType(t)
+	schema := NewOrderedMap()
+
+	switch {
+	case t == timeType:
+		schema.Set("type", "string")
+		schema.Set("format", "date-time")
+		return schema, nil
+	case t.Implements(mapperType) || reflect.PtrTo(t).Implements(mapperType):
+		schema.Set("type", "object")
+		return schema, nil
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		schema.Set("type", "string")
+		return schema, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		schema.Set("type", "boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema.Set("type", "integer")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema.Set("type", "integer")
+		schema.Set("minimum", 0)
+	case reflect.Float32, reflect.Float64:
+		schema.Set("type", "number")
+	case reflect.String:
+		schema.Set("type", "string")
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			schema.Set("type", "string")
+			schema.Set("contentEncoding", "base64")
+			break
+		}
+
+		items, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		schema.Set("type", "array")
+		schema.Set("items", items)
+		if t.Kind() == reflect.Array {
+			schema.Set("minItems", t.Len())
+			schema.Set("maxItems", t.Len())
+		}
+	case reflect.Map:
+		values, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		schema.Set("type", "object")
+		schema.Set("additionalProperties", values)
+	case reflect.Interface:
+		// any value
+	case reflect.Struct:
+		if t.Name() == "" {
+			if err := g.object(schemaOf(t, g.tagName, g.seen), schema); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		ref, err := g.ref(t)
+		if err != nil {
+			return nil, err
+		}
+		schema.Set("$ref", ref)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+
+	return schema, nil
+}
+
+// ref returns the reference to the named struct type t, which is added to
+// the definitions if it's not there yet.
+func (g *jsonSchemaGen) ref(t reflect.Type) (string, error) {
+	if t == g.root {
+		return "#", nil
+	}
+
+	if name, ok := g.names[t]; ok {
+		return "#/$defs/" + name, nil
+	}
+
+	// types of different packages may have the same name
+	name := t.Name()
+	for i := 2; g.used[name]; i++ {
+		name = t.Name() + strconv.Itoa(i)
+	}
+	g.used[name] = true
+	g.names[t] = name
+
+	// add the definition before describing it, so recursive types refer to
+	// it and the definitions keep the order in which they are found
+	def := NewOrderedMap()
+	g.defs.Set(name, def)
+
+	if err := g.object(schemaOf(t, g.tagName, g.seen), def); err != nil {
+		return "", err
+	}
+
+	return "#/$defs/" + name, nil
+}
+
+// nullable returns the schema allowing null in addition to the values allowed
+// by schema. A schema referencing a definition is wrapped in "anyOf".
+func nullable(schema *OrderedMap) *OrderedMap {
+	if typ, ok := schema.Get("type"); ok {
+		if s, ok := typ.(string); ok {
+			schema.Set("type", []string{s, "null"})
+		}
+		return schema
+	}
+
+	if ref, ok := schema.Get("$ref"); ok {
+		out := NewOrderedMap()
+		out.Set("anyOf", []interface{}{
+			map[string]interface{}{"$ref": ref},
+			map[string]interface{}{"type": "null"},
+		})
+		return out
+	}
+
+	// a schema without a type, such as the one of interface{}, allows
+	// null already
+	return schema
+}
+
+// schemaType returns the JSON type of schema, ignoring "null" if the schema
+// allows it.
+func schemaType(schema *OrderedMap) string {
+	typ, _ := schema.Get("type")
+
+	switch typ := typ.(type) {
+	case string:
+		return typ
+	case []string:
+		return typ[0]
+	}
+
+	return ""
+}
+
+// validationRules adds the constraints of the "validate" tag value rules to
+// the schema. The boolean is true if the rules contain "required".
+func validationRules(schema *OrderedMap, rules string) (bool, error) {
+	if rules == "" {
+		return false, nil
+	}
+
+	typ := schemaType(schema)
+
+	var lengthMin, lengthMax string
+	switch typ {
+	case "string":
+		lengthMin, lengthMax = "minLength", "maxLength"
+	case "array":
+		lengthMin, lengthMax = "minItems", "maxItems"
+	case "object":
+		lengthMin, lengthMax = "minProperties", "maxProperties"
+	}
+
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		key, value := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			key, value = rule[:i], rule[i+1:]
+		}
+
+		switch key {
+		case "required":
+			required = true
+		case "min", "gte", "max", "lte", "gt", "lt", "len":
+			if typ == "integer" || typ == "number" {
+				n, err := jsonNumber(value)
+				if err != nil {
+					return false, fmt.Errorf("invalid rule %q", rule)
+				}
+
+				switch key {
+				case "min", "gte":
+					schema.Set("minimum", n)
+				case "max", "lte":
+					schema.Set("maximum", n)
+				case "gt":
+					schema.Set("exclusiveMinimum", n)
+				case "lt":
+					schema.Set("exclusiveMaximum", n)
+				case "len":
+					schema.Set("const", n)
+				}
+				continue
+			}
+
+			if lengthMin == "" {
+				continue
+			}
+
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return false, fmt.Errorf("invalid rule %q", rule)
+			}
+
+			switch key {
+			case "min", "gte":
+				schema.Set(lengthMin, n)
+			case "max", "lte":
+				schema.Set(lengthMax, n)
+			case "gt":
+				schema.Set(lengthMin, n+1)
+			case "lt":
+				// no length is less than zero
+				if n == 0 {
+					return false, fmt.Errorf("invalid rule %q", rule)
+				}
+				schema.Set(lengthMax, n-1)
+			case "len":
+				schema.Set(lengthMin, n)
+				schema.Set(lengthMax, n)
+			}
+		case "oneof":
+			enum, err := enumValues(typ, strings.Fields(value))
+			if err != nil {
+				return false, fmt.Errorf("invalid rule %q", rule)
+			}
+			schema.Set("enum", enum)
+		case "email":
+			schema.Set("format", "email")
+		case "url":
+			schema.Set("format", "uri")
+		case "uuid":
+			schema.Set("format", "uuid")
+		}
+	}
+
+	return required, nil
+}
+
+// enumValues converts the given values to the JSON type typ.
+func enumValues(typ string, values []string) ([]interface{}, error) {
+	enum := make([]interface{}, len(values))
+
+	for i, value := range values {
+		var err error
+		switch typ {
+		case "integer":
+			if _, err = strconv.ParseInt(value, 10, 64); err == nil {
+				enum[i], err = jsonNumber(value)
+			}
+		case "number":
+			enum[i], err = jsonNumber(value)
+		case "boolean":
+			enum[i], err = strconv.ParseBool(value)
+		default:
+			enum[i] = value
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return enum, nil
+}
+
+// jsonNumber returns the number s as it's written, if it's a valid JSON
+// number.
+func jsonNumber(s string) (json.Number, error) {
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return "", err
+	}
+
+	if !json.Valid([]byte(s)) {
+		return "", fmt.Errorf("invalid number %q", s)
+	}
+
+	return json.Number(s), nil
+}
+
+// elemType returns the type t points to, dereferencing all pointers.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
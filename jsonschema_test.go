@@ -0,0 +1,309 @@
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type jsonSchemaAddress struct {
+	City string `structs:"city" doc:"Name of the city"`
+	Zip  string `structs:"zip,omitempty" validate:"len=5"`
+}
+
+type jsonSchemaMeta struct {
+	Version int `structs:"version"`
+}
+
+type jsonSchemaServer struct {
+	Name     string                       `structs:"name" validate:"min=1,max=64"`
+	Port     int                          `structs:"port,omitempty" validate:"required,gte=1,lte=65535"`
+	Mode     string                       `structs:"mode,omitempty" validate:"oneof=dev prod"`
+	Level    int                          `structs:"level,omitempty" validate:"oneof=1 2 3"`
+	Ratio    float64                      `structs:"ratio,omitempty" validate:"gt=0,lt=1"`
+	Tags     []string                     `structs:"tags,omitempty" validate:"max=3"`
+	Hash     []byte                       `structs:"hash,omitempty"`
+	Created  time.Time                    `structs:"created"`
+	Day      time.Time                    `structs:"day,format=2006-01-02"`
+	Address  *jsonSchemaAddress           `structs:"address,omitempty"`
+	Backups  []jsonSchemaAddress          `structs:"backups,omitempty"`
+	Labels   map[string]string            `structs:"labels,omitempty"`
+	Meta     jsonSchemaMeta               `structs:",flatten"`
+	Extra    interface{}                  `structs:"extra,omitempty"`
+	Parent   *jsonSchemaServer            `structs:"parent,omitempty"`
+	Inline   struct{ A bool }             `structs:"inline"`
+	Ignored  string                       `structs:"-"`
+	Pair     [2]uint                      `structs:"pair,omitempty"`
+	Contacts map[string]jsonSchemaAddress `structs:"contacts,omitempty"`
+	secret   string
+}
+
+func TestJSONSchema(t *testing.T) {
+	out, err := JSONSchema(&jsonSchemaServer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "name": {
+      "type": "string",
+      "minLength": 1,
+      "maxLength": 64
+    },
+    "port": {
+      "type": "integer",
+      "minimum": 1,
+      "maximum": 65535
+    },
+    "mode": {
+      "type": "string",
+      "enum": [
+        "dev",
+        "prod"
+      ]
+    },
+    "level": {
+      "type": "integer",
+      "enum": [
+        1,
+        2,
+        3
+      ]
+    },
+    "ratio": {
+      "type": "number",
+      "exclusiveMinimum": 0,
+      "exclusiveMaximum": 1
+    },
+    "tags": {
+      "type": [
+        "array",
+        "null"
+      ],
+      "items": {
+        "type": "string"
+      },
+      "maxItems": 3
+    },
+    "hash": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "contentEncoding": "base64"
+    },
+    "created": {
+      "type": "string",
+      "format": "date-time"
+    },
+    "day": {
+      "type": "string"
+    },
+    "address": {
+      "anyOf": [
+        {
+          "$ref": "#/$defs/jsonSchemaAddress"
+        },
+        {
+          "type": "null"
+        }
+      ]
+    },
+    "backups": {
+      "type": [
+        "array",
+        "null"
+      ],
+      "items": {
+        "$ref": "#/$defs/jsonSchemaAddress"
+      }
+    },
+    "labels": {
+      "type": [
+        "object",
+        "null"
+      ],
+      "additionalProperties": {
+        "type": "string"
+      }
+    },
+    "version": {
+      "type": "integer"
+    },
+    "extra": {},
+    "parent": {
+      "anyOf": [
+        {
+          "$ref": "#"
+        },
+        {
+          "type": "null"
+        }
+      ]
+    },
+    "inline": {
+      "type": "object",
+      "properties": {
+        "A": {
+          "type": "boolean"
+        }
+      },
+      "required": [
+        "A"
+      ]
+    },
+    "pair": {
+      "type": "array",
+      "items": {
+        "type": "integer",
+        "minimum": 0
+      },
+      "minItems": 2,
+      "maxItems": 2
+    },
+    "contacts": {
+      "type": [
+        "object",
+        "null"
+      ],
+      "additionalProperties": {
+        "$ref": "#/$defs/jsonSchemaAddress"
+      }
+    }
+  },
+  "required": [
+    "name",
+    "port",
+    "created",
+    "day",
+    "version",
+    "inline"
+  ],
+  "$defs": {
+    "jsonSchemaAddress": {
+      "type": "object",
+      "properties": {
+        "city": {
+          "type": "string",
+          "description": "Name of the city"
+        },
+        "zip": {
+          "type": "string",
+          "minLength": 5,
+          "maxLength": 5
+        }
+      },
+      "required": [
+        "city"
+      ]
+    }
+  }
+}`
+
+	if string(out) != expected {
+		t.Errorf("JSONSchema should return\n%s\ngot:\n%s", expected, out)
+	}
+
+	if !json.Valid(out) {
+		t.Error("JSONSchema should return valid JSON")
+	}
+}
+
+func TestJSONSchema_Nullable(t *testing.T) {
+	type T struct {
+		Addr *jsonSchemaAddress `structs:"addr"`
+		Tags []string           `structs:"tags"`
+		Hits map[string]int     `structs:"hits"`
+		When *time.Time         `structs:"when,format=2006-01-02"`
+	}
+
+	out, err := JSONSchema(T{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Properties map[string]map[string]interface{}
+		Required   []string
+	}
+	if err := json.Unmarshal(out, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(schema.Required) != 4 {
+		t.Errorf("All fields should be required, got: %v", schema.Required)
+	}
+
+	// Map returns nil for all of them
+	for key, val := range Map(T{}) {
+		if val != nil && !reflect.ValueOf(val).IsNil() {
+			t.Fatalf("Map should return nil for %s, got: %v", key, val)
+		}
+	}
+
+	for _, key := range []string{"tags", "hits", "when"} {
+		typ, ok := schema.Properties[key]["type"].([]interface{})
+		if !ok || len(typ) != 2 || typ[1] != "null" {
+			t.Errorf("%s should allow null, got: %v", key, schema.Properties[key])
+		}
+	}
+
+	anyOf, ok := schema.Properties["addr"]["anyOf"].([]interface{})
+	if !ok || len(anyOf) != 2 {
+		t.Errorf("addr should allow null, got: %v", schema.Properties["addr"])
+	}
+}
+
+func TestJSONSchema_Type(t *testing.T) {
+	a, err := JSONSchema(jsonSchemaAddress{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := JSONSchema(reflect.TypeOf(jsonSchemaAddress{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(a) != string(b) {
+		t.Errorf("JSONSchema should return the same schema for a reflect.Type, got:\n%s", b)
+	}
+}
+
+func TestJSONSchema_Errors(t *testing.T) {
+	type Chan struct {
+		C chan int
+	}
+
+	if _, err := JSONSchema(Chan{}); err == nil {
+		t.Error("JSONSchema should return an error for a channel")
+	}
+
+	type Rule struct {
+		A int `validate:"min=abc"`
+	}
+
+	if _, err := JSONSchema(Rule{}); err == nil {
+		t.Error("JSONSchema should return an error for an invalid rule")
+	}
+
+	type Length struct {
+		A string `validate:"lt=0"`
+	}
+
+	if _, err := JSONSchema(Length{}); err == nil {
+		t.Error("JSONSchema should return an error for a negative maximum length")
+	}
+
+	type Enum struct {
+		A int `validate:"oneof=1 two"`
+	}
+
+	if _, err := JSONSchema(Enum{}); err == nil {
+		t.Error("JSONSchema should return an error for an invalid enum")
+	}
+}
@@ -0,0 +1,101 @@
+package structs
+
+import "reflect"
+
+// Option configures a *Struct created with NewWith. The configuration is
+// inherited by all nested structs and by the fields returned by the Struct.
+type Option func(*Struct)
+
+// NewWith returns a new *Struct with the struct s, configured with the given
+// options. It panics if the s's kind is not struct. Example:
+//
+//   s := structs.NewWith(server,
+//       structs.WithTagName("json"),
+//       structs.WithMaxDepth(3),
+//       structs.WithNilStructs(structs.OmitNilStructs),
+//   )
+func NewWith(s interface{}, opts ...Option) *Struct {
+	strct := New(s)
+	for _, opt := range opts {
+		opt(strct)
+	}
+
+	return strct
+}
+
+// WithTagName sets the key of the struct field's tag, see Struct.TagName.
+func WithTagName(name string) Option {
+	return func(s *Struct) {
+		s.TagName = name
+	}
+}
+
+// WithNameFunc sets the function which converts field names to keys, see
+// Struct.NameFunc.
+func WithNameFunc(fn func(name string) string) Option {
+	return func(s *Struct) {
+		s.NameFunc = fn
+	}
+}
+
+// WithMaxDepth limits the levels of nested structs, see Struct.MaxDepth.
+func WithMaxDepth(depth int) Option {
+	return func(s *Struct) {
+		s.MaxDepth = depth
+	}
+}
+
+// WithNilStructs sets the handling of nil pointers to structs, see
+// Struct.NilStructs.
+func WithNilStructs(policy NilStructPolicy) Option {
+	return func(s *Struct) {
+		s.NilStructs = policy
+	}
+}
+
+// WithConverter registers fn to convert values of the type t, see
+// Struct.RegisterConverter.
+func WithConverter(t reflect.Type, fn ConverterFunc) Option {
+	return func(s *Struct) {
+		s.RegisterConverter(t, fn)
+	}
+}
+
+// WithStrict enables the strict mode, see Struct.Strict.
+func WithStrict() Option {
+	return func(s *Struct) {
+		s.Strict = true
+	}
+}
+
+// WithUnexported includes unexported fields in the read-only mode, see
+// Struct.IncludeUnexported.
+func WithUnexported() Option {
+	return func(s *Struct) {
+		s.IncludeUnexported = true
+	}
+}
+
+// WithTextMarshaler enables or disables the conversion of values
+// implementing encoding.TextMarshaler to strings, see Struct.UseTextMarshaler.
+func WithTextMarshaler(enabled bool) Option {
+	return func(s *Struct) {
+		s.UseTextMarshaler = enabled
+	}
+}
+
+// WithExpandInterfaces converts structs held by interfaces, see
+// Struct.ExpandInterfaces.
+func WithExpandInterfaces() Option {
+	return func(s *Struct) {
+		s.ExpandInterfaces = true
+	}
+}
+
+// WithConvertContainers converts all nested maps and slices, see
+// Struct.ConvertContainers.
+func WithConvertContainers() Option {
+	return func(s *Struct) {
+		s.ConvertContainers = true
+	}
+}
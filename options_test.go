@@ -0,0 +1,95 @@
+package structs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWith(t *testing.T) {
+	type Inner struct {
+		CreatedAt time.Time
+		Secret    string `json:"-"`
+		Next      *Inner `json:"next"`
+	}
+
+	type T struct {
+		Name  string `json:"name"`
+		Inner Inner
+		Empty *Inner
+	}
+
+	at := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	v := T{
+		Name: "gopher",
+		Inner: Inner{
+			CreatedAt: at,
+			Secret:    "secret",
+			Next:      &Inner{CreatedAt: at},
+		},
+	}
+
+	s := NewWith(v,
+		WithTagName("json"),
+		WithNameFunc(strings.ToLower),
+		WithMaxDepth(2),
+		WithNilStructs(OmitNilStructs),
+		WithConverter(reflect.TypeOf(time.Time{}), func(v reflect.Value) (interface{}, error) {
+			return v.Interface().(time.Time).Year(), nil
+		}),
+	)
+
+	expected := map[string]interface{}{
+		"name": "gopher",
+		"inner": map[string]interface{}{
+			"createdat": 2018,
+			"next":      &Inner{CreatedAt: at},
+		},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values := []interface{}{"gopher", 2018, &Inner{CreatedAt: at}}
+	if vs := s.Values(); !reflect.DeepEqual(vs, values) {
+		t.Errorf("Values should return %v, got: %v", values, vs)
+	}
+}
+
+func TestNewWith_Strict(t *testing.T) {
+	type T struct {
+		A int `structs:",string"`
+	}
+
+	if _, err := NewWith(T{}).MapErr(); err != nil {
+		t.Errorf("MapErr should not return an error, got: %v", err)
+	}
+
+	if _, err := NewWith(T{}, WithStrict()).MapErr(); err == nil {
+		t.Error("MapErr should return an error in strict mode")
+	}
+}
+
+func TestNewWith_Fields(t *testing.T) {
+	type C struct {
+		Visible string
+		Hidden  string `json:"-"`
+	}
+
+	type B struct {
+		C C
+	}
+
+	type A struct {
+		B B
+	}
+
+	s := NewWith(&A{}, WithTagName("json"))
+
+	fields := s.Field("B").Field("C").Fields()
+	if len(fields) != 1 || fields[0].Name() != "Visible" {
+		t.Errorf("Fields should skip the field hidden by the json tag, got: %d fields", len(fields))
+	}
+}
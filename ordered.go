@@ -0,0 +1,124 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MapItem is a single key/value pair of an OrderedMap.
+type MapItem struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedMap is a map which preserves the insertion order of its keys. It's
+// returned by Struct.OrderedMap and is marshaled to a JSON object with the
+// keys in the same order. The zero value is an empty map ready to use.
+type OrderedMap struct {
+	items []MapItem
+	index map[string]int
+}
+
+// NewOrderedMap returns a new empty *OrderedMap.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{
+		index: make(map[string]int),
+	}
+}
+
+// Set sets the value for the given key. A new key is appended to the end,
+// while an existing key keeps its position.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if i, ok := m.index[key]; ok {
+		m.items[i].Value = value
+		return
+	}
+
+	if m.index == nil {
+		m.index = make(map[string]int)
+	}
+
+	m.index[key] = len(m.items)
+	m.items = append(m.items, MapItem{Key: key, Value: value})
+}
+
+// Get returns the value for the given key. The boolean returns true if the key
+// was found.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	i, ok := m.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	return m.items[i].Value, true
+}
+
+// Len returns the number of keys.
+func (m *OrderedMap) Len() int {
+	return len(m.items)
+}
+
+// Keys returns the keys in their order.
+func (m *OrderedMap) Keys() []string {
+	keys := make([]string, len(m.items))
+	for i, item := range m.items {
+		keys[i] = item.Key
+	}
+
+	return keys
+}
+
+// Items returns the key/value pairs in their order.
+func (m *OrderedMap) Items() []MapItem {
+	items := make([]MapItem, len(m.items))
+	copy(items, m.items)
+	return items
+}
+
+// MarshalJSON implements the json.Marshaler interface. The keys of the JSON
+// object are written in their order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, item := range m.items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// OrderedMap is the same as Map, but the keys are in the declaration order of
+// the fields. Nested structs are converted to *OrderedMap as well, so they
+// keep their order too. Fields flattened with the "flatten" option are placed
+// at the position of their parent field.
+func (s *Struct) OrderedMap() *OrderedMap {
+	out, _ := s.orderedMap()
+	return out
+}
+
+func (s *Struct) orderedMap() (*OrderedMap, error) {
+	n := *s
+	n.ordered = true
+
+	out := NewOrderedMap()
+	err := n.fill(out.Set)
+	return out, err
+}
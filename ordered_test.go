@@ -0,0 +1,103 @@
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	type Address struct {
+		Zip  string `structs:"zip"`
+		City string `structs:"city"`
+	}
+
+	type Meta struct {
+		Version int
+	}
+
+	type Server struct {
+		Name      string    `structs:"name"`
+		Port      int       `structs:"port"`
+		Address   Address   `structs:"address"`
+		Meta      Meta      `structs:",flatten"`
+		Backups   []Address `structs:"backups"`
+		Empty     string    `structs:"empty,omitempty"`
+		Addresses map[string]Address
+	}
+
+	s := &Server{
+		Name:      "gopher",
+		Port:      80,
+		Address:   Address{Zip: "10115", City: "Berlin"},
+		Meta:      Meta{Version: 2},
+		Backups:   []Address{{Zip: "75001", City: "Paris"}},
+		Addresses: map[string]Address{"home": {Zip: "34000"}},
+	}
+
+	m := New(s).OrderedMap()
+
+	keys := []string{"name", "port", "address", "Version", "backups", "Addresses"}
+	if !reflect.DeepEqual(m.Keys(), keys) {
+		t.Errorf("OrderedMap should have the keys %v, got: %v", keys, m.Keys())
+	}
+
+	if v, ok := m.Get("port"); !ok || v != 80 {
+		t.Errorf("Get should return 80 for port, got: %v", v)
+	}
+
+	if _, ok := m.Get("empty"); ok {
+		t.Error("Get should not find an omitted key")
+	}
+
+	address, _ := m.Get("address")
+	if keys := address.(*OrderedMap).Keys(); !reflect.DeepEqual(keys, []string{"zip", "city"}) {
+		t.Errorf("Nested structs should keep their order, got: %v", keys)
+	}
+
+	out, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"name":"gopher","port":80,"address":{"zip":"10115","city":"Berlin"},` +
+		`"Version":2,"backups":[{"zip":"75001","city":"Paris"}],` +
+		`"Addresses":{"home":{"zip":"34000","city":""}}}`
+
+	if string(out) != expected {
+		t.Errorf("OrderedMap should be marshaled to\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestOrderedMap_Set(t *testing.T) {
+	m := NewOrderedMap()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("b", 3)
+
+	expected := []MapItem{{Key: "b", Value: 3}, {Key: "a", Value: 2}}
+	if !reflect.DeepEqual(m.Items(), expected) {
+		t.Errorf("Items should return %v, got: %v", expected, m.Items())
+	}
+
+	if m.Len() != 2 {
+		t.Errorf("Len should return 2, got: %d", m.Len())
+	}
+}
+
+func TestOrderedMap_ZeroValue(t *testing.T) {
+	var m OrderedMap
+
+	if _, ok := m.Get("a"); ok {
+		t.Error("Get should not find a key in an empty map")
+	}
+
+	m.Set("a", 1)
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Errorf("Get should return 1 for a, got: %v", v)
+	}
+
+	if out, err := json.Marshal(&m); err != nil || string(out) != `{"a":1}` {
+		t.Errorf("OrderedMap should be marshaled to {\"a\":1}, got: %s", out)
+	}
+}
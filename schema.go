@@ -0,0 +1,181 @@
+package structs
+
+import "reflect"
+
+// TypeSchema describes a struct type and all of its fields.
+type TypeSchema struct {
+	// Name is the name of the type within its package. It's empty for
+	// unnamed types.
+	Name string
+
+	// PkgPath is the import path of the package which defines the type.
+	PkgPath string
+
+	// Type is the described struct type.
+	Type reflect.Type
+
+	// Fields contains all fields in their declaration order, including
+	// unexported fields and fields ignored with a tag value of "-".
+	Fields []*FieldSchema
+}
+
+// FieldSchema describes a single struct field.
+type FieldSchema struct {
+	// Name is the name of the field in the Go source.
+	Name string
+
+	// TagName is the name given in the field's tag, such as "name" for a tag
+	// value of "name,omitempty". It's empty if the tag contains no name.
+	TagName string
+
+	// Options contains the options given in the field's tag, such as
+	// "omitempty" or "format=hex".
+	Options []string
+
+	// Kind is the kind of the field, such as reflect.Ptr for pointers.
+	Kind reflect.Kind
+
+	// Type is the type of the field.
+	Type reflect.Type
+
+	// Elem is the element type of a pointer, slice, array, map or channel
+	// field. It's nil for other kinds.
+	Elem reflect.Type
+
+	// Key is the key type of a map field. It's nil for other kinds.
+	Key reflect.Type
+
+	// Embedded is true for anonymous fields.
+	Embedded bool
+
+	// Exported is true if the field is exported.
+	Exported bool
+
+	// Pointer is true if the field is a pointer.
+	Pointer bool
+
+	// Ignored is true if the field's tag value is "-".
+	Ignored bool
+
+	// Nested is the schema of the struct type held by the field, either
+	// directly or through pointers, slices, arrays and maps, such as Address
+	// for a field of type []*Address. Structs without exported fields, such
+	// as time.Time, have no nested schema. Recursive types share the same
+	// *TypeSchema instance.
+	Nested *TypeSchema
+
+	// Tag is the raw tag of the field.
+	Tag reflect.StructTag
+
+	// Tags contains all key/value pairs of the raw tag.
+	Tags map[string]string
+}
+
+// Field returns the schema of the field with the given Go name. The boolean
+// returns true if the field was found.
+func (t *TypeSchema) Field(name string) (*FieldSchema, bool) {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+// Schema returns the schema of the struct type of s. The tag names and
+// options are read from the s's TagName key.
+func (s *Struct) Schema() *TypeSchema {
+	return schemaOf(s.value.Type(), s.TagName, make(map[reflect.Type]*TypeSchema))
+}
+
+// Schema returns the schema of the given struct, pointer to struct or a
+// reflect.Type of a struct. The tag names and options are read from the
+// DefaultTagName key. It panics if the kind is not struct.
+func Schema(v interface{}) *TypeSchema {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		panic("not struct")
+	}
+
+	return schemaOf(t, DefaultTagName, make(map[reflect.Type]*TypeSchema))
+}
+
+// schemaOf returns the schema of the struct type t. The seen map contains the
+// schemas built so far, so recursive types don't recurse forever.
+func schemaOf(t reflect.Type, tagName string, seen map[reflect.Type]*TypeSchema) *TypeSchema {
+	if ts, ok := seen[t]; ok {
+		return ts
+	}
+
+	ts := &TypeSchema{
+		Name:    t.Name(),
+		PkgPath: t.PkgPath(),
+		Type:    t,
+	}
+	seen[t] = ts
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get(tagName)
+		name, opts := parseTag(tag)
+
+		fs := &FieldSchema{
+			Name:     field.Name,
+			TagName:  name,
+			Options:  []string(opts),
+			Kind:     field.Type.Kind(),
+			Type:     field.Type,
+			Embedded: field.Anonymous,
+			Exported: field.PkgPath == "",
+			Pointer:  field.Type.Kind() == reflect.Ptr,
+			Ignored:  tag == "-",
+			Tag:      field.Tag,
+			Tags:     parseStructTag(field.Tag),
+		}
+
+		if fs.Ignored {
+			fs.TagName = ""
+		}
+
+		switch fs.Kind {
+		case reflect.Map:
+			fs.Key = field.Type.Key()
+			fs.Elem = field.Type.Elem()
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+			fs.Elem = field.Type.Elem()
+		}
+
+		if st := structType(field.Type); st != nil && hasExportedFields(st) {
+			fs.Nested = schemaOf(st, tagName, seen)
+		}
+
+		ts.Fields = append(ts.Fields, fs)
+	}
+
+	return ts
+}
+
+// structType returns the struct type held by t through pointers, slices,
+// arrays and maps, or nil if there is none.
+func structType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Struct:
+			return t
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaNode struct {
+	Value    int           `structs:"value"`
+	Children []*schemaNode `structs:"children,omitempty"`
+}
+
+func TestSchema(t *testing.T) {
+	type Address struct {
+		City string `structs:"city" json:"city"`
+	}
+
+	type Base struct {
+		ID int
+	}
+
+	type Server struct {
+		Base
+		Name      string             `structs:"name,omitempty" json:"name"`
+		Address   *Address           `structs:"address"`
+		Backups   []Address          `structs:"backups"`
+		Labels    map[string]Address `structs:"labels"`
+		Created   time.Time          `structs:"created,format=2006-01-02"`
+		Ignored   string             `structs:"-"`
+		unexposed int
+	}
+
+	for _, v := range []interface{}{Server{}, &Server{}, reflect.TypeOf(Server{})} {
+		s := Schema(v)
+
+		if s.Name != "Server" || s.Type != reflect.TypeOf(Server{}) {
+			t.Errorf("Schema should describe Server, got: %s", s.Name)
+		}
+
+		if len(s.Fields) != 8 {
+			t.Fatalf("Schema should have 8 fields, got: %d", len(s.Fields))
+		}
+	}
+
+	s := Schema(Server{})
+
+	base := s.Fields[0]
+	if !base.Embedded || base.Nested == nil || base.Nested.Name != "Base" {
+		t.Errorf("Base should be an embedded field with a nested schema, got: %+v", base)
+	}
+
+	name, _ := s.Field("Name")
+	if name.TagName != "name" || !reflect.DeepEqual(name.Options, []string{"omitempty"}) {
+		t.Errorf("Name should have the tag name and options, got: %q %v", name.TagName, name.Options)
+	}
+
+	tags := map[string]string{"structs": "name,omitempty", "json": "name"}
+	if !reflect.DeepEqual(name.Tags, tags) {
+		t.Errorf("Name should have the tags %v, got: %v", tags, name.Tags)
+	}
+
+	address, _ := s.Field("Address")
+	if !address.Pointer || address.Kind != reflect.Ptr || address.Elem != reflect.TypeOf(Address{}) {
+		t.Errorf("Address should be a pointer to Address, got: %+v", address)
+	}
+
+	if address.Nested == nil || address.Nested.Fields[0].TagName != "city" {
+		t.Errorf("Address should have a nested schema, got: %+v", address.Nested)
+	}
+
+	backups, _ := s.Field("Backups")
+	if backups.Kind != reflect.Slice || backups.Nested != address.Nested {
+		t.Errorf("Backups should share the nested schema of Address, got: %+v", backups)
+	}
+
+	labels, _ := s.Field("Labels")
+	if labels.Key != reflect.TypeOf("") || labels.Elem != reflect.TypeOf(Address{}) {
+		t.Errorf("Labels should have string keys and Address values, got: %+v", labels)
+	}
+
+	created, _ := s.Field("Created")
+	if created.Nested != nil {
+		t.Errorf("time.Time should have no nested schema, got: %+v", created.Nested)
+	}
+
+	ignored, _ := s.Field("Ignored")
+	if !ignored.Ignored {
+		t.Error("Ignored should be ignored")
+	}
+
+	unexposed, _ := s.Field("unexposed")
+	if unexposed.Exported {
+		t.Error("unexposed should not be exported")
+	}
+}
+
+func TestSchema_Recursive(t *testing.T) {
+	s := Schema(schemaNode{})
+
+	children, ok := s.Field("Children")
+	if !ok {
+		t.Fatal("Children should exist")
+	}
+
+	if children.Nested != s {
+		t.Error("Recursive types should share the same schema")
+	}
+}
+
+func TestSchema_TagName(t *testing.T) {
+	type T struct {
+		A string `json:"a,omitempty"`
+	}
+
+	s := New(T{})
+	s.TagName = "json"
+
+	if f := s.Schema().Fields[0]; f.TagName != "a" {
+		t.Errorf("Schema should use the TagName of the Struct, got: %q", f.TagName)
+	}
+}
+
+func TestSchema_NonStruct(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("Schema should panic for a non struct")
+		}
+	}()
+
+	Schema(1)
+}
@@ -0,0 +1,259 @@
+package structs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// SQLTagName is the struct field's tag key used by Columns, Args,
+// Placeholders, ScanTargets and UpdateSet.
+var SQLTagName = "db"
+
+// PlaceholderStyle defines the format of the parameter placeholders in SQL
+// statements.
+type PlaceholderStyle int
+
+const (
+	// QuestionPlaceholder is the "?" format, used by MySQL and SQLite.
+	QuestionPlaceholder PlaceholderStyle = iota
+
+	// DollarPlaceholder is the "$1" format, used by PostgreSQL.
+	DollarPlaceholder
+)
+
+// placeholder returns the placeholder of the n-th parameter, starting with 1.
+func (p PlaceholderStyle) placeholder(n int) string {
+	if p == DollarPlaceholder {
+		return "$" + strconv.Itoa(n)
+	}
+
+	return "?"
+}
+
+// Columns returns the column names of the given struct. The default column
+// name is the struct field name, which can be changed with the "db" key in
+// the struct field's tag value. Example:
+//
+//   // Field is stored in the column "user_name".
+//   Name string `db:"user_name"`
+//
+// A tag value with the content of "-" ignores that particular field. Fields
+// of embedded structs are added as if they were fields of the struct itself.
+// A tag value with the option of "flatten" adds the fields of a nested struct
+// as columns prefixed with the name of the field and an underscore. Example:
+//
+//   // Columns are "address_city" and "address_zip".
+//   Address Address `db:"address,flatten"`
+//
+// All other structs, such as time.Time, are a single column. It panics if s's
+// kind is not struct or if an embedded or flattened struct contains itself,
+// such as a flattened Parent *Node field in Node, which would add columns
+// forever. The same applies to Args, Placeholders, ScanTargets and UpdateSet.
+func Columns(s interface{}) []string {
+	columns := sqlColumns(strctVal(s).Type())
+
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+
+	return names
+}
+
+// Args returns the values of the columns of the given struct, in the same
+// order as Columns. Fields of a nil embedded struct pointer are nil. It
+// panics if s's kind is not struct.
+func Args(s interface{}) []interface{} {
+	v := strctVal(s)
+	columns := sqlColumns(v.Type())
+
+	args := make([]interface{}, len(columns))
+	for i, col := range columns {
+		args[i] = readValue(fieldByIndex(v, col.index))
+	}
+
+	return args
+}
+
+// Placeholders returns a comma separated list of placeholders for each column
+// of the given struct, in the given style, such as "?, ?, ?" or "$1, $2, $3".
+// Example:
+//
+//   query := fmt.Sprintf("INSERT INTO users (%s) VALUES (%s)",
+//       strings.Join(structs.Columns(u), ", "),
+//       structs.Placeholders(u, structs.QuestionPlaceholder))
+//   _, err := db.Exec(query, structs.Args(u)...)
+//
+// It panics if s's kind is not struct.
+func Placeholders(s interface{}, style PlaceholderStyle) string {
+	columns := sqlColumns(strctVal(s).Type())
+
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = style.placeholder(i + 1)
+	}
+
+	return strings.Join(placeholders, ", ")
+}
+
+// ScanTargets returns pointers to the fields of the given struct, in the same
+// order as Columns, so they can be passed to sql.Rows.Scan. Example:
+//
+//   rows, err := db.Query("SELECT " + strings.Join(structs.Columns(u), ", ") + " FROM users")
+//   ...
+//   err = rows.Scan(structs.ScanTargets(&u)...)
+//
+// Nil embedded struct pointers are allocated. It panics if s is not a pointer
+// to a struct.
+func ScanTargets(s interface{}) []interface{} {
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic("not struct pointer")
+	}
+	v = v.Elem()
+
+	columns := sqlColumns(v.Type())
+
+	targets := make([]interface{}, len(columns))
+	for i, col := range columns {
+		targets[i] = allocFieldByIndex(v, col.index).Addr().Interface()
+	}
+
+	return targets
+}
+
+// UpdateSet returns the comma separated "column = ?" assignments for an UPDATE
+// statement and their arguments. Fields with the option of "omitempty" are
+// skipped if they have a zero value. Example:
+//
+//   // Name is not updated if it's empty
+//   Name string `db:"name,omitempty"`
+//
+//   set, args := structs.UpdateSet(u, structs.DollarPlaceholder)
+//   _, err := db.Exec("UPDATE users SET "+set+" WHERE id = $"+strconv.Itoa(len(args)+1), append(args, id)...)
+//
+// It panics if s's kind is not struct.
+func UpdateSet(s interface{}, style PlaceholderStyle) (string, []interface{}) {
+	v := strctVal(s)
+
+	var set []string
+	var args []interface{}
+
+	for _, col := range sqlColumns(v.Type()) {
+		val := fieldByIndex(v, col.index)
+		if col.opts.Has("omitempty") && isZero(val) {
+			continue
+		}
+
+		args = append(args, readValue(val))
+		set = append(set, col.name+" = "+style.placeholder(len(args)))
+	}
+
+	return strings.Join(set, ", "), args
+}
+
+// sqlColumn describes a single SQL column and the path to its field.
+type sqlColumn struct {
+	name  string
+	index []int
+	field reflect.StructField
+	opts  tagOptions
+}
+
+// sqlColumns returns the columns for the given struct type, flattening
+// embedded structs. It panics for recursive types, see Columns.
+func sqlColumns(t reflect.Type) []sqlColumn {
+	columns, err := sqlColumnsErr(t)
+	if err != nil {
+		panic(err)
+	}
+
+	return columns
+}
+
+// sqlColumnsErr is like sqlColumns, but returns an error for recursive types.
+func sqlColumnsErr(t reflect.Type) ([]sqlColumn, error) {
+	var columns []sqlColumn
+	err := appendSQLColumns(&columns, t, "", nil, map[reflect.Type]bool{t: true})
+	return columns, err
+}
+
+// appendSQLColumns appends the columns of the struct type t to columns. path
+// contains the struct types which are currently being flattened, so it
+// returns an error for a struct which contains itself.
+func appendSQLColumns(columns *[]sqlColumn, t reflect.Type, prefix string, index []int, path map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// we can't access the value of unexported fields
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get(SQLTagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, tagOpts := parseTag(tag)
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		ft := elemType(field.Type)
+		embedded := field.Anonymous && name == ""
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if (embedded || tagOpts.Has("flatten")) && !isSQLValue(ft) {
+			if path[ft] {
+				return fmt.Errorf("field %s: recursive type %s can't be flattened", field.Name, ft)
+			}
+
+			nestedPrefix := prefix + name + "_"
+			if embedded {
+				nestedPrefix = prefix
+			}
+
+			path[ft] = true
+			err := appendSQLColumns(columns, ft, nestedPrefix, fieldIndex, path)
+			delete(path, ft)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		*columns = append(*columns, sqlColumn{
+			name:  prefix + name,
+			index: fieldIndex,
+			field: field,
+			opts:  tagOpts,
+		})
+	}
+
+	return nil
+}
+
+// isSQLValue returns true if values of the type t are stored in a single
+// column, which are all types except structs, unless they are handled by a
+// database driver, such as time.Time and sql.NullString.
+func isSQLValue(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return true
+	}
+
+	p := reflect.PtrTo(t)
+	return t.Implements(valuerType) || p.Implements(valuerType) || p.Implements(scannerType)
+}
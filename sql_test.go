@@ -0,0 +1,238 @@
+package structs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSQLDriver is a database/sql driver which records the executed
+// statements and returns the rows of fakeSQLRows for every query.
+type fakeSQLDriver struct {
+	execs   []string
+	args    [][]driver.Value
+	columns []string
+	rows    [][]driver.Value
+}
+
+var fakeSQL = &fakeSQLDriver{}
+
+func init() {
+	sql.Register("structs-fake", fakeSQL)
+}
+
+func (d *fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	return &fakeSQLConn{d: d}, nil
+}
+
+type fakeSQLConn struct {
+	d *fakeSQLDriver
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeSQLConn) Close() error { return nil }
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeSQLStmt struct {
+	d     *fakeSQLDriver
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error  { return nil }
+func (s *fakeSQLStmt) NumInput() int { return -1 }
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.execs = append(s.d.execs, s.query)
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeSQLRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type fakeSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string { return r.columns }
+func (r *fakeSQLRows) Close() error      { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestSQL(t *testing.T) {
+	type Base struct {
+		ID int64 `db:"id"`
+	}
+
+	type User struct {
+		Base
+		Name    string         `db:"name"`
+		Email   string         `db:"email,omitempty"`
+		Created time.Time      `db:"created_at"`
+		Nick    sql.NullString `db:"nick"`
+		Secret  string         `db:"-"`
+		Age     int
+	}
+
+	created := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	u := User{
+		Base:    Base{ID: 1},
+		Name:    "gopher",
+		Created: created,
+		Nick:    sql.NullString{String: "go", Valid: true},
+		Secret:  "secret",
+		Age:     9,
+	}
+
+	columns := []string{"id", "name", "email", "created_at", "nick", "Age"}
+	if c := Columns(u); !reflect.DeepEqual(c, columns) {
+		t.Errorf("Columns should return %v, got: %v", columns, c)
+	}
+
+	if p := Placeholders(&u, QuestionPlaceholder); p != "?, ?, ?, ?, ?, ?" {
+		t.Errorf("Placeholders should return ?, ?, ?, ?, ?, ?, got: %s", p)
+	}
+
+	if p := Placeholders(u, DollarPlaceholder); p != "$1, $2, $3, $4, $5, $6" {
+		t.Errorf("Placeholders should return $1, $2, $3, $4, $5, $6, got: %s", p)
+	}
+
+	set, args := UpdateSet(u, DollarPlaceholder)
+	if set != "id = $1, name = $2, created_at = $3, nick = $4, Age = $5" {
+		t.Errorf("UpdateSet should skip the empty email, got: %s", set)
+	}
+
+	expectedArgs := []interface{}{int64(1), "gopher", created, u.Nick, 9}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("UpdateSet should return the args %v, got: %v", expectedArgs, args)
+	}
+
+	db, err := sql.Open("structs-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	query := "INSERT INTO users (" + strings.Join(Columns(u), ", ") + ") VALUES (" +
+		Placeholders(u, QuestionPlaceholder) + ")"
+	if _, err := db.Exec(query, Args(u)...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedValues := []driver.Value{int64(1), "gopher", "", created, "go", int64(9)}
+	if last := fakeSQL.args[len(fakeSQL.args)-1]; !reflect.DeepEqual(last, expectedValues) {
+		t.Errorf("Exec should be called with %v, got: %v", expectedValues, last)
+	}
+
+	fakeSQL.columns = columns
+	fakeSQL.rows = [][]driver.Value{
+		{int64(2), "gordon", "gordon@example.com", created, nil, int64(7)},
+	}
+
+	rows, err := db.Query("SELECT " + strings.Join(Columns(u), ", ") + " FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var got User
+	for rows.Next() {
+		if err := rows.Scan(ScanTargets(&got)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := User{
+		Base:    Base{ID: 2},
+		Name:    "gordon",
+		Email:   "gordon@example.com",
+		Created: created,
+		Age:     7,
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Scan should fill %+v, got: %+v", expected, got)
+	}
+}
+
+func TestScanTargets_EmbeddedPointer(t *testing.T) {
+	type Base struct {
+		ID int
+	}
+
+	type T struct {
+		*Base
+		Name string
+	}
+
+	var v T
+	targets := ScanTargets(&v)
+
+	if len(targets) != 2 || v.Base == nil {
+		t.Fatalf("ScanTargets should allocate the embedded pointer, got: %d targets", len(targets))
+	}
+
+	*targets[0].(*int) = 1
+	if v.ID != 1 {
+		t.Errorf("ScanTargets should return a pointer to ID, got: %d", v.ID)
+	}
+
+	if args := Args(T{Name: "a"}); !reflect.DeepEqual(args, []interface{}{nil, "a"}) {
+		t.Errorf("Args should return nil for a nil embedded struct, got: %v", args)
+	}
+}
+
+func TestScanTargets_NonPointer(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("ScanTargets should panic for a non pointer")
+		}
+	}()
+
+	ScanTargets(struct{ A int }{})
+}
+
+type sqlNode struct {
+	ID     int      `db:"id"`
+	Parent *sqlNode `db:"parent,flatten"`
+}
+
+func TestColumns_RecursiveType(t *testing.T) {
+	type Tree struct {
+		*Tree
+		ID int `db:"id"`
+	}
+
+	for _, v := range []interface{}{sqlNode{}, Tree{}} {
+		func() {
+			defer func() {
+				if err := recover(); err == nil {
+					t.Errorf("Columns should panic for the recursive type %T", v)
+				}
+			}()
+
+			Columns(v)
+		}()
+	}
+}
@@ -3,6 +3,7 @@ package structs
 
 import (
 	"fmt"
+	"sort"
 
 	"reflect"
 )
@@ -14,21 +15,110 @@ var (
 	DefaultTagName = "structs" // struct's field default tag name
 )
 
+// NilStructPolicy defines how a nil pointer to a struct is handled by Map,
+// Values and the zero checks.
+type NilStructPolicy int
+
+const (
+	// NilStructsAsNil handles a nil pointer to a struct as a nil value. It's
+	// added as nil to maps and values, and it's zero.
+	NilStructsAsNil NilStructPolicy = iota
+
+	// OmitNilStructs skips nil pointers to structs. They are neither added
+	// to maps and values nor checked by the zero checks.
+	OmitNilStructs
+
+	// ExpandNilStructs handles a nil pointer to a struct as a zero value of
+	// that struct. It's converted to a map of zero values the same way as
+	// any other nested struct. A nil pointer to a struct type which is
+	// already being iterated, such as the Next field of a linked list node,
+	// is handled as a nil value, as expanding it would never end.
+	ExpandNilStructs
+)
+
 // Struct encapsulates a struct type to provide several high level functions
 // around the struct.
 type Struct struct {
 	raw     interface{}
 	value   reflect.Value
 	TagName string
+
+	// NameFunc converts the names of fields, which have no name in their
+	// tag, to the keys used by Map and the paths returned by ZeroFields. For
+	// example it can be used to convert the keys to snake case. Field names
+	// are used as they are if it's nil.
+	NameFunc func(name string) string
+
+	// IncludeUnexported enables a read-only inspection mode in which Fields,
+	// Names, Map, Values and the zero checks include unexported fields.
+	// Their values are copied, so they can be read but never modified, and
+	// Field.Set and Field.Zero still return an error for them.
+	IncludeUnexported bool
+
+	// UseTextMarshaler converts values implementing encoding.TextMarshaler
+	// to strings in Map and Values, such as net.IP. time.Time values are
+	// always kept as they are and structs which only get MarshalText from an
+	// embedded field, such as an embedded time.Time, are iterated as usual. It's enabled by New and can be disabled to
+	// keep all such values as they are.
+	UseTextMarshaler bool
+
+	// ExpandInterfaces makes Map look through interface values, so structs
+	// held by interface fields, []interface{} and map[string]interface{}
+	// values are converted recursively as well. It's disabled by default, so
+	// such slices and maps are kept as they are.
+	ExpandInterfaces bool
+
+	// ConvertContainers makes Map convert all nested maps to
+	// map[string]interface{} and all nested slices and arrays, except byte
+	// slices, to []interface{}. By default only the ones which contain
+	// structs are converted and all others are kept as they are.
+	ConvertContainers bool
+
+	// Strict makes MapErr and ValuesErr return an error for misconfigured
+	// tags, such as the "string" option on a field which doesn't implement
+	// fmt.Stringer. Such fields are not included in the output.
+	Strict bool
+
+	// MaxDepth limits the number of struct levels which are iterated by Map,
+	// Values, IsZero, HasZero and the other recursive functions. Nested
+	// structs below that level are handled as if they had the "omitnested"
+	// option. With a MaxDepth of 1 only the fields of s itself are iterated.
+	// Zero or a negative value means no limit.
+	MaxDepth int
+
+	// NilStructs defines how fields which are nil pointers to structs are
+	// handled. It doesn't apply to fields with the "omitnested" option or
+	// to the elements of slices and maps.
+	NilStructs NilStructPolicy
+
+	// converters contains the functions registered via RegisterConverter.
+	converters map[reflect.Type]ConverterFunc
+
+	// ordered builds nested structs as *OrderedMap instead of a map, see
+	// OrderedMap.
+	ordered bool
+
+	// depth is the level of a nested *Struct, starting with zero for the top
+	// level struct.
+	depth int
+
+	// visited contains the pointers, maps and slices which are currently
+	// being iterated, so we can detect cycles.
+	visited map[ref]bool
+
+	// parents contains the types of the structs enclosing s, so nil
+	// pointers to a recursive type are not expanded forever.
+	parents []reflect.Type
 }
 
 // New returns a new *Struct with the struct s. It panics if the s's kind is
 // not struct.
 func New(s interface{}) *Struct {
 	return &Struct{
-		raw:     s,
-		value:   strctVal(s),
-		TagName: DefaultTagName,
+		raw:              s,
+		value:            strctVal(s),
+		TagName:          DefaultTagName,
+		UseTextMarshaler: true,
 	}
 }
 
@@ -49,9 +139,25 @@ func New(s interface{}) *Struct {
 // A tag value with the content of "string" uses the stringer to get the value. Example:
 //
 //   // The value will be output of Animal's String() func.
-//   // Map will panic if Animal does not implement String().
+//   // If Animal does not implement String(), the output of fmt.Sprint is used.
 //   Field *Animal `structs:"field,string"`
 //
+// A tag value with the option of "format" formats the value with the given fmt
+// verb, time layout or one of the "hex" and "base64" encodings for byte slices.
+// The formatted value is a string. Example:
+//
+//   // Field appears in map as "2006-01-02"
+//   Created time.Time `structs:"created,format=2006-01-02"`
+//
+//   // Field appears in map as "3.14"
+//   Price float64 `structs:"price,format=%.2f"`
+//
+//   // Field appears in map as "cafe"
+//   Hash []byte `structs:"hash,format=hex"`
+//
+// Note that the format can't contain a comma. Use MapErr to get the error of
+// a format which can't be applied to a field.
+//
 // A tag value with the option of "flatten" used in a struct field is to flatten its fields
 // in the output map. Example:
 //
@@ -76,14 +182,38 @@ func New(s interface{}) *Struct {
 //   // the field is skipped if empty.
 //   Field string `structs:",omitempty"`
 //
+// Values of a type registered via RegisterConverter or implementing the Mapper
+// interface are converted with it, including the values of nested structs,
+// maps and slices. Use MapErr to get the errors of the conversion.
+//
+// A field referencing a struct, map or slice which is currently being
+// converted, such as a pointer back to a parent struct, is set to nil to
+// prevent an infinite recursion. Use MaxDepth to limit the levels of nested
+// structs which are converted.
+//
+// A field which is a nil pointer to a struct is added as nil by default. Use
+// NilStructs to omit such fields or to convert them to a map of zero values.
+//
 // Note that only exported fields of a struct can be accessed, non exported
-// fields will be neglected.
+// fields will be neglected unless IncludeUnexported is enabled.
 func (s *Struct) Map() map[string]interface{} {
 	out := make(map[string]interface{})
 	s.FillMap(out)
 	return out
 }
 
+// MapErr is the same as Map, but it returns the first error returned by a
+// converter or a Mapper implementation. Fields which can't be converted are
+// not included in the map.
+func (s *Struct) MapErr() (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	err := s.fill(func(key string, val interface{}) {
+		out[key] = val
+	})
+
+	return out, err
+}
+
 // FillMap is the same as Map. Instead of returning the output, it fills the
 // given map.
 func (s *Struct) FillMap(out map[string]interface{}) {
@@ -91,62 +221,115 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 		return
 	}
 
+	s.fill(func(key string, val interface{}) {
+		out[key] = val
+	})
+}
+
+// fill converts the fields of s the same way as described in Map and passes
+// each key/value pair to the given set function in the fields order. A field
+// which can't be converted is skipped and the first error is returned.
+func (s *Struct) fill(set func(key string, val interface{})) error {
+	s = s.walk()
+
+	var firstErr error
+
 	fields := s.structFields()
 
 	for _, field := range fields {
 		name := field.Name
 		val := s.value.FieldByName(name)
-		isSubStruct := false
 		var finalVal interface{}
+		var err error
 
 		tagName, tagOpts := parseTag(field.Tag.Get(s.TagName))
 		if tagName != "" {
 			name = tagName
+		} else if s.NameFunc != nil {
+			name = s.NameFunc(name)
 		}
 
 		// if the value is a zero value and the field is marked as omitempty do
 		// not include
-		if tagOpts.Has("omitempty") {
-			zero := reflect.Zero(val.Type()).Interface()
-			current := val.Interface()
+		if tagOpts.Has("omitempty") && isZero(val) {
+			continue
+		}
 
-			if reflect.DeepEqual(current, zero) {
+		if format, ok := tagOpts.Value("format"); ok {
+			formatted, err := formatOption(val, format)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("field %s: %s", field.Name, err)
+				}
 				continue
 			}
-		}
 
-		if !tagOpts.Has("omitnested") {
-			finalVal = s.nested(val)
+			set(name, formatted)
+			continue
+		}
 
-			v := reflect.ValueOf(val.Interface())
-			if v.Kind() == reflect.Ptr {
-				v = v.Elem()
+		if tagOpts.Has("string") {
+			str, err := stringOption(val, s.Strict)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("field %s: %s", field.Name, err)
+				}
+				continue
 			}
 
-			switch v.Kind() {
-			case reflect.Map, reflect.Struct:
-				isSubStruct = true
-			}
+			set(name, str)
+			continue
+		}
+
+		val, ok := s.nilStruct(val, tagOpts)
+		if !ok {
+			continue
+		}
+
+		if !tagOpts.Has("omitnested") {
+			finalVal, err = s.nested(val)
 		} else {
-			finalVal = val.Interface()
+			var ok bool
+			finalVal, ok, err = s.convert(val)
+			if !ok {
+				finalVal = readValue(val)
+			}
 		}
 
-		if tagOpts.Has("string") {
-			s, ok := val.Interface().(fmt.Stringer)
-			if ok {
-				out[name] = s.String()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("field %s: %s", field.Name, err)
 			}
 			continue
 		}
 
-		if isSubStruct && (tagOpts.Has("flatten")) {
-			for k := range finalVal.(map[string]interface{}) {
-				out[k] = finalVal.(map[string]interface{})[k]
+		if !tagOpts.Has("flatten") {
+			set(name, finalVal)
+			continue
+		}
+
+		switch m := finalVal.(type) {
+		case *OrderedMap:
+			for _, item := range m.items {
+				set(item.Key, item.Value)
 			}
-		} else {
-			out[name] = finalVal
+		case map[string]interface{}:
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				set(k, m[k])
+			}
+		default:
+			// nothing to flatten, ie: time.Time
+			set(name, finalVal)
 		}
 	}
+
+	return firstErr
 }
 
 // Values converts the given s struct's field values to a []interface{}.  A
@@ -169,9 +352,33 @@ func (s *Struct) FillMap(out map[string]interface{}) {
 //   // Field is skipped if empty
 //   Field string `structs:",omitempty"`
 //
+// A tag value with the option of "format" formats the value the same way as
+// described in Map. Example:
+//
+//   // Field is added as "2006-01-02"
+//   Field time.Time `structs:",format=2006-01-02"`
+//
+// Values of a type registered via RegisterConverter or implementing the Mapper
+// interface are converted with it. Use ValuesErr to get the errors of the
+// conversion. A nested struct referencing one of its parents is added as nil
+// and a nil pointer to a struct is handled according to NilStructs, the same
+// as described in Map.
+//
 // Note that only exported fields of a struct can be accessed, non exported
-// fields  will be neglected.
+// fields  will be neglected unless IncludeUnexported is enabled.
 func (s *Struct) Values() []interface{} {
+	t, _ := s.ValuesErr()
+	return t
+}
+
+// ValuesErr is the same as Values, but it returns the first error returned by
+// a converter or a Mapper implementation. Fields which can't be converted are
+// not included in the values.
+func (s *Struct) ValuesErr() ([]interface{}, error) {
+	s = s.walk()
+
+	var firstErr error
+
 	fields := s.structFields()
 
 	var t []interface{}
@@ -183,33 +390,72 @@ func (s *Struct) Values() []interface{} {
 
 		// if the value is a zero value and the field is marked as omitempty do
 		// not include
-		if tagOpts.Has("omitempty") {
-			zero := reflect.Zero(val.Type()).Interface()
-			current := val.Interface()
+		if tagOpts.Has("omitempty") && isZero(val) {
+			continue
+		}
 
-			if reflect.DeepEqual(current, zero) {
+		if format, ok := tagOpts.Value("format"); ok {
+			formatted, err := formatOption(val, format)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("field %s: %s", field.Name, err)
+				}
 				continue
 			}
+
+			t = append(t, formatted)
+			continue
 		}
 
 		if tagOpts.Has("string") {
-			s, ok := val.Interface().(fmt.Stringer)
-			if ok {
-				t = append(t, s.String())
+			str, err := stringOption(val, s.Strict)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("field %s: %s", field.Name, err)
+				}
+				continue
 			}
+
+			t = append(t, str)
 			continue
 		}
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
+		val, ok := s.nilStruct(val, tagOpts)
+		if !ok {
+			continue
+		}
+
+		converted, ok, err := s.convert(val)
+		if ok {
+			t = append(t, converted)
+			continue
+		}
+
+		if err == nil && isStructValue(val) && !tagOpts.Has("omitnested") &&
+			s.descend() {
+			leave, ok := s.enter(val)
+			if !ok {
+				// the struct references itself
+				t = append(t, nil)
+				continue
+			}
+
 			// look out for embedded structs, and convert them to a
 			// []interface{} to be added to the final values slice
-			t = append(t, Values(val.Interface())...)
-		} else {
-			t = append(t, val.Interface())
+			var values []interface{}
+			values, err = s.sub(val).ValuesErr()
+			t = append(t, values...)
+			leave()
+		} else if err == nil {
+			t = append(t, readValue(val))
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("field %s: %s", field.Name, err)
 		}
 	}
 
-	return t
+	return t, firstErr
 }
 
 // Fields returns a slice of Fields. A struct tag with the content of "-"
@@ -218,9 +464,13 @@ func (s *Struct) Values() []interface{} {
 //   // Field is ignored by this package.
 //   Field bool `structs:"-"`
 //
-// It panics if s's kind is not struct.
+// The fields are selected the same way as for Map: unexported fields are
+// skipped unless IncludeUnexported is enabled. The selection depends only on
+// the struct type, so fields with the option of "omitempty" are always
+// included. Use Keys to get the keys of Map for the current value. It panics
+// if s's kind is not struct.
 func (s *Struct) Fields() []*Field {
-	return getFields(s.value, s.TagName)
+	return getFields(s.value, s.TagName, s.IncludeUnexported)
 }
 
 // Names returns a slice of field names. A struct tag with the content of "-"
@@ -229,9 +479,11 @@ func (s *Struct) Fields() []*Field {
 //   // Field is ignored by this package.
 //   Field bool `structs:"-"`
 //
-// It panics if s's kind is not struct.
+// The names are the Go names of the fields returned by Fields, not the names
+// given in their tags. Use Keys to get the keys of Map. It panics if s's kind
+// is not struct.
 func (s *Struct) Names() []string {
-	fields := getFields(s.value, s.TagName)
+	fields := getFields(s.value, s.TagName, s.IncludeUnexported)
 
 	names := make([]string, len(fields))
 
@@ -242,7 +494,56 @@ func (s *Struct) Names() []string {
 	return names
 }
 
-func getFields(v reflect.Value, tagName string) []*Field {
+// Keys returns the keys of the map returned by Map for the current value of
+// the struct, in the declaration order of the fields. The keys are the tag
+// names or field names, fields with the option of "omitempty" are skipped if
+// they are empty and the keys of fields with the option of "flatten" are
+// replaced by the keys of their nested struct. Example:
+//
+//   type Server struct {
+//       Name    string `structs:"name"`
+//       Port    int    `structs:"port,omitempty"`
+//       Enabled bool
+//   }
+//
+//   // Keys are "name" and "Enabled"
+//   keys := structs.New(Server{Name: "gopher"}).Keys()
+//
+// It panics if s's kind is not struct.
+func (s *Struct) Keys() []string {
+	m, _ := s.orderedMap()
+	return m.Keys()
+}
+
+// Pointers returns a slice of pointers to the exported fields, in the same
+// order as Fields. Unexported fields are skipped, even if IncludeUnexported is
+// enabled. Example:
+//
+//   u := &User{}
+//   err := rows.Scan(structs.New(u).Pointers()...)
+//
+// It panics if the struct wasn't passed to New as a pointer, as its fields are
+// not addressable then.
+func (s *Struct) Pointers() []interface{} {
+	var pointers []interface{}
+
+	for _, field := range s.Fields() {
+		if !field.IsExported() {
+			continue
+		}
+
+		p, err := field.Addr()
+		if err != nil {
+			panic(err)
+		}
+
+		pointers = append(pointers, p)
+	}
+
+	return pointers
+}
+
+func getFields(v reflect.Value, tagName string, unexported bool) []*Field {
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
@@ -253,14 +554,21 @@ func getFields(v reflect.Value, tagName string) []*Field {
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
+		// skip unexported fields the same way as Map does, unless they are
+		// explicitly requested in the read-only mode
+		if field.PkgPath != "" && !unexported {
+			continue
+		}
 
 		if tag := field.Tag.Get(tagName); tag == "-" {
 			continue
 		}
 
 		f := &Field{
-			field: field,
-			value: v.FieldByName(field.Name),
+			field:      field,
+			value:      v.FieldByName(field.Name),
+			defaultTag: tagName,
+			unexported: unexported,
 		}
 
 		fields = append(fields, f)
@@ -296,6 +604,7 @@ func (s *Struct) FieldOk(name string) (*Field, bool) {
 		field:      field,
 		value:      s.value.FieldByName(name),
 		defaultTag: s.TagName,
+		unexported: s.IncludeUnexported,
 	}, true
 }
 
@@ -313,9 +622,17 @@ func (s *Struct) FieldOk(name string) (*Field, bool) {
 //   Field time.Time     `structs:"myName,omitnested"`
 //   Field *http.Request `structs:",omitnested"`
 //
+// Fields whose type implements an "IsZero() bool" method, such as time.Time,
+// or has a function registered via RegisterZeroFunc are checked with it
+// instead of being iterated further. A pointer back to a struct which is
+// currently being checked, such as a parent struct, is never zero. A nil
+// pointer to a struct is zero, unless NilStructs says otherwise.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected. It panics if s's kind is not struct.
 func (s *Struct) IsZero() bool {
+	s = s.walk()
+
 	fields := s.structFields()
 
 	for _, field := range fields {
@@ -323,8 +640,20 @@ func (s *Struct) IsZero() bool {
 
 		_, tagOpts := parseTag(field.Tag.Get(s.TagName))
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
-			ok := IsZero(val.Interface())
+		val, ok := s.nilStruct(val, tagOpts)
+		if !ok {
+			continue
+		}
+
+		if s.iterable(val, tagOpts) {
+			leave, ok := s.enter(val)
+			if !ok {
+				// a struct referencing itself is never zero
+				return false
+			}
+
+			ok = s.sub(val).IsZero()
+			leave()
 			if !ok {
 				return false
 			}
@@ -332,13 +661,7 @@ func (s *Struct) IsZero() bool {
 			continue
 		}
 
-		// zero value of the given field, such as "" for string, 0 for int
-		zero := reflect.Zero(val.Type()).Interface()
-
-		//  current value of the given field
-		current := val.Interface()
-
-		if !reflect.DeepEqual(current, zero) {
+		if !isZero(val) {
 			return false
 		}
 	}
@@ -360,9 +683,17 @@ func (s *Struct) IsZero() bool {
 //   Field time.Time     `structs:"myName,omitnested"`
 //   Field *http.Request `structs:",omitnested"`
 //
+// Fields whose type implements an "IsZero() bool" method, such as time.Time,
+// or has a function registered via RegisterZeroFunc are checked with it
+// instead of being iterated further. A pointer back to a struct which is
+// currently being checked, such as a parent struct, is never zero. A nil
+// pointer to a struct is zero, unless NilStructs says otherwise.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected. It panics if s's kind is not struct.
 func (s *Struct) HasZero() bool {
+	s = s.walk()
+
 	fields := s.structFields()
 
 	for _, field := range fields {
@@ -370,8 +701,20 @@ func (s *Struct) HasZero() bool {
 
 		_, tagOpts := parseTag(field.Tag.Get(s.TagName))
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
-			ok := HasZero(val.Interface())
+		val, ok := s.nilStruct(val, tagOpts)
+		if !ok {
+			continue
+		}
+
+		if s.iterable(val, tagOpts) {
+			leave, ok := s.enter(val)
+			if !ok {
+				// the struct references itself, so it's not zero
+				continue
+			}
+
+			ok = s.sub(val).HasZero()
+			leave()
 			if ok {
 				return true
 			}
@@ -379,13 +722,7 @@ func (s *Struct) HasZero() bool {
 			continue
 		}
 
-		// zero value of the given field, such as "" for string, 0 for int
-		zero := reflect.Zero(val.Type()).Interface()
-
-		//  current value of the given field
-		current := val.Interface()
-
-		if reflect.DeepEqual(current, zero) {
+		if isZero(val) {
 			return true
 		}
 	}
@@ -393,6 +730,69 @@ func (s *Struct) HasZero() bool {
 	return false
 }
 
+// ZeroFields returns the paths of all fields which are not initialized (zero
+// value). Nested structs are iterated the same way as in HasZero and their
+// fields are returned as dot separated paths, such as "Server.Name". The
+// "structs" key in the struct's field tag value is used as the name of a
+// field. Example:
+//
+//   // Field appears as "server.name" if the field Name is empty
+//   Server Server `structs:"server"`
+//
+// A struct tag with the content of "-" ignores the checking of that particular
+// field and a value with the option of "omitnested" stops iterating further if
+// the type is a struct. It panics if s's kind is not struct.
+func (s *Struct) ZeroFields() []string {
+	return s.fieldPaths("", true)
+}
+
+// NonZeroFields returns the paths of all fields which are initialized (non
+// zero value). For more info refer to ZeroFields. It panics if s's kind is not
+// struct.
+func (s *Struct) NonZeroFields() []string {
+	return s.fieldPaths("", false)
+}
+
+// fieldPaths returns the paths of all fields whose zero state is equal to the
+// given zero argument.
+func (s *Struct) fieldPaths(prefix string, zero bool) []string {
+	s = s.walk()
+
+	var paths []string
+
+	for _, field := range s.structFields() {
+		val := s.value.FieldByName(field.Name)
+
+		tagName, tagOpts := parseTag(field.Tag.Get(s.TagName))
+
+		name := prefix + field.Name
+		if tagName != "" {
+			name = prefix + tagName
+		} else if s.NameFunc != nil {
+			name = prefix + s.NameFunc(field.Name)
+		}
+
+		val, ok := s.nilStruct(val, tagOpts)
+		if !ok {
+			continue
+		}
+
+		if s.iterable(val, tagOpts) {
+			if leave, ok := s.enter(val); ok {
+				paths = append(paths, s.sub(val).fieldPaths(name+".", zero)...)
+				leave()
+				continue
+			}
+		}
+
+		if isZero(val) == zero {
+			paths = append(paths, name)
+		}
+	}
+
+	return paths
+}
+
 // Name returns the structs's type name within its package. For more info refer
 // to Name() function.
 func (s *Struct) Name() string {
@@ -409,8 +809,9 @@ func (s *Struct) structFields() []reflect.StructField {
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		// we can't access the value of unexported fields
-		if field.PkgPath != "" {
+		// we can't access the value of unexported fields, unless they are
+		// explicitly requested in the read-only mode
+		if field.PkgPath != "" && !s.IncludeUnexported {
 			continue
 		}
 
@@ -426,8 +827,10 @@ func (s *Struct) structFields() []reflect.StructField {
 }
 
 func strctVal(s interface{}) reflect.Value {
-	v := reflect.ValueOf(s)
+	return strctValue(reflect.ValueOf(s))
+}
 
+func strctValue(v reflect.Value) reflect.Value {
 	// if pointer get the underlying element≤
 	for v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -470,6 +873,12 @@ func Names(s interface{}) []string {
 	return New(s).Names()
 }
 
+// Keys returns the keys of the map returned by Map. For more info refer to
+// Struct types Keys() method.  It panics if s's kind is not struct.
+func Keys(s interface{}) []string {
+	return New(s).Keys()
+}
+
 // IsZero returns true if all fields is equal to a zero value. For more info
 // refer to Struct types IsZero() method.  It panics if s's kind is not struct.
 func IsZero(s interface{}) bool {
@@ -482,6 +891,20 @@ func HasZero(s interface{}) bool {
 	return New(s).HasZero()
 }
 
+// ZeroFields returns the paths of all fields which have a zero value. For more
+// info refer to Struct types ZeroFields() method.  It panics if s's kind is
+// not struct.
+func ZeroFields(s interface{}) []string {
+	return New(s).ZeroFields()
+}
+
+// NonZeroFields returns the paths of all fields which have a non zero value.
+// For more info refer to Struct types NonZeroFields() method.  It panics if
+// s's kind is not struct.
+func NonZeroFields(s interface{}) []string {
+	return New(s).NonZeroFields()
+}
+
 // IsStruct returns true if the given variable is a struct or a pointer to
 // struct.
 func IsStruct(s interface{}) bool {
@@ -505,80 +928,196 @@ func Name(s interface{}) string {
 }
 
 // nested retrieves recursively all types for the given value and returns the
-// nested value.
-func (s *Struct) nested(val reflect.Value) interface{} {
-	var finalVal interface{}
+// nested value. The first error of a converter is returned, but the remaining
+// values are still converted.
+func (s *Struct) nested(val reflect.Value) (interface{}, error) {
+	if converted, ok, err := s.convert(val); ok || err != nil {
+		return converted, err
+	}
 
-	v := reflect.ValueOf(val.Interface())
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
+	if s.ExpandInterfaces && val.Kind() == reflect.Interface && !val.IsNil() {
+		return s.nested(val.Elem())
 	}
 
-	switch v.Kind() {
+	leave, ok := s.enter(val)
+	if !ok {
+		// a reference to a value which is being converted
+		return nil, nil
+	}
+	defer leave()
+
+	var finalVal interface{}
+	var firstErr error
+
+	switch elem(val).Kind() {
 	case reflect.Struct:
-		n := New(val.Interface())
-		n.TagName = s.TagName
-		m := n.Map()
+		if !s.descend() {
+			finalVal = readValue(val)
+			break
+		}
+
+		var m interface{}
+		var n int
+		var err error
+		if s.ordered {
+			var o *OrderedMap
+			o, err = s.sub(val).orderedMap()
+			m, n = o, o.Len()
+		} else {
+			var mm map[string]interface{}
+			mm, err = s.sub(val).MapErr()
+			m, n = mm, len(mm)
+		}
+
+		if err != nil {
+			return nil, err
+		}
 
 		// do not add the converted value if there are no exported fields, ie:
 		// time.Time
-		if len(m) == 0 {
-			finalVal = val.Interface()
+		if n == 0 || !hasExportedFields(elem(val).Type()) {
+			finalVal = readValue(val)
 		} else {
 			finalVal = m
 		}
 	case reflect.Map:
-		// get the element type of the map
-		mapElem := val.Type()
-		switch val.Type().Kind() {
-		case reflect.Ptr, reflect.Array, reflect.Map,
-			reflect.Slice, reflect.Chan:
-			mapElem = val.Type().Elem()
-			if mapElem.Kind() == reflect.Ptr {
-				mapElem = mapElem.Elem()
-			}
+		// only iterate over maps which contain structs, ie:
+		// map[string]StructType, map[int][]*StructType,
+		// map[string]map[string]StructType, unless all containers are
+		// converted
+		if val.Kind() != reflect.Map ||
+			(!s.ConvertContainers && !s.containsStructs(val.Type().Elem())) {
+			finalVal = readValue(val)
+			break
 		}
 
-		// only iterate over struct types, ie: map[string]StructType,
-		// map[string][]StructType,
-		if mapElem.Kind() == reflect.Struct ||
-			(mapElem.Kind() == reflect.Slice &&
-				mapElem.Elem().Kind() == reflect.Struct) {
-			m := make(map[string]interface{}, val.Len())
-			for _, k := range val.MapKeys() {
-				m[k.String()] = s.nested(val.MapIndex(k))
+		m := make(map[string]interface{}, val.Len())
+		for _, k := range val.MapKeys() {
+			v, err := s.nested(val.MapIndex(k))
+			if err != nil && firstErr == nil {
+				firstErr = err
 			}
-			finalVal = m
-			break
+			m[mapKey(k)] = v
 		}
-
-		// TODO(arslan): should this be optional?
-		finalVal = val.Interface()
+		finalVal = m
 	case reflect.Slice, reflect.Array:
-		if val.Type().Kind() == reflect.Interface {
-			finalVal = val.Interface()
+		// do not iterate of non struct types, just pass the value. Ie: []int,
+		// []string, co... We only iterate further if it contains structs.
+		// i.e []foo, []*foo, [][]foo or [2]foo
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			finalVal = readValue(val)
 			break
 		}
 
-		// TODO(arslan): should this be optional?
-		// do not iterate of non struct types, just pass the value. Ie: []int,
-		// []string, co... We only iterate further if it's a struct.
-		// i.e []foo or []*foo
-		if val.Type().Elem().Kind() != reflect.Struct &&
-			!(val.Type().Elem().Kind() == reflect.Ptr &&
-				val.Type().Elem().Elem().Kind() == reflect.Struct) {
-			finalVal = val.Interface()
+		elemType := val.Type().Elem()
+		convert := s.containsStructs(elemType) ||
+			(s.ConvertContainers && elemType.Kind() != reflect.Uint8)
+		if !convert {
+			finalVal = readValue(val)
 			break
 		}
 
 		slices := make([]interface{}, val.Len())
 		for x := 0; x < val.Len(); x++ {
-			slices[x] = s.nested(val.Index(x))
+			v, err := s.nested(val.Index(x))
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			slices[x] = v
 		}
 		finalVal = slices
 	default:
-		finalVal = val.Interface()
+		finalVal = readValue(val)
+	}
+
+	return finalVal, firstErr
+}
+
+// sub returns a new *Struct for the nested struct value v, which inherits the
+// configuration of s.
+func (s *Struct) sub(v reflect.Value) *Struct {
+	return &Struct{
+		value:             elem(v),
+		TagName:           s.TagName,
+		NameFunc:          s.NameFunc,
+		IncludeUnexported: s.IncludeUnexported,
+		UseTextMarshaler:  s.UseTextMarshaler,
+		ExpandInterfaces:  s.ExpandInterfaces,
+		ConvertContainers: s.ConvertContainers,
+		Strict:            s.Strict,
+		MaxDepth:          s.MaxDepth,
+		NilStructs:        s.NilStructs,
+		converters:        s.converters,
+		ordered:           s.ordered,
+		depth:             s.depth + 1,
+		visited:           s.visited,
+		parents:           append(s.parents[:len(s.parents):len(s.parents)], s.value.Type()),
+	}
+}
+
+// elem returns the value that the interface or pointer v contains, the same
+// way as reflect.ValueOf(v.Interface()).Elem() does, but it works on values of
+// unexported fields too. It returns v itself for any other kind and an invalid
+// value for a nil pointer.
+func elem(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// nilStruct applies the NilStructs policy if v is a nil pointer to a struct
+// and the field has no "omitnested" option. It returns the value to be used
+// instead of v, which is invalid if the field is handled as nil. The boolean
+// is false if the field has to be skipped.
+func (s *Struct) nilStruct(v reflect.Value, tagOpts tagOptions) (reflect.Value, bool) {
+	if v.Kind() != reflect.Ptr || !v.IsNil() ||
+		v.Type().Elem().Kind() != reflect.Struct || tagOpts.Has("omitnested") {
+		return v, true
+	}
+
+	switch s.NilStructs {
+	case OmitNilStructs:
+		return v, false
+	case ExpandNilStructs:
+		if !s.expanding(v.Type().Elem()) {
+			return reflect.New(v.Type().Elem()).Elem(), true
+		}
 	}
 
-	return finalVal
+	return reflect.Value{}, true
+}
+
+// expanding returns true if the struct type t is s's own type or the type of
+// one of its enclosing structs.
+func (s *Struct) expanding(t reflect.Type) bool {
+	if s.value.Type() == t {
+		return true
+	}
+
+	for _, p := range s.parents {
+		if p == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// iterable returns true if the fields of the nested struct value v are
+// iterated by the zero checks.
+func (s *Struct) iterable(v reflect.Value, tagOpts tagOptions) bool {
+	return isStructValue(v) && !tagOpts.Has("omitnested") &&
+		!hasCustomZero(v.Type()) && s.descend()
+}
+
+// isStructValue is the same as IsStruct, but it works on values of unexported
+// fields too.
+func isStructValue(v reflect.Value) bool {
+	return elem(v).Kind() == reflect.Struct
 }
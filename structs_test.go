@@ -1207,6 +1207,60 @@ func TestHasZero_Anonymous(t *testing.T) {
 	}
 }
 
+func TestZeroFields(t *testing.T) {
+	type Address struct {
+		City string `structs:"city"`
+		Zip  string `structs:"zip"`
+	}
+
+	type Config struct {
+		Name    string
+		Port    int     `structs:"port"`
+		Address Address `structs:"address"`
+		Backup  Address `structs:"backup,omitnested"`
+		Parent  *Address
+		Skip    string `structs:"-"`
+	}
+
+	c := &Config{
+		Name:    "server",
+		Address: Address{City: "Berlin"},
+		Backup:  Address{City: "Paris"},
+	}
+
+	zero := ZeroFields(c)
+	expected := []string{"port", "address.zip", "Parent"}
+	if !reflect.DeepEqual(zero, expected) {
+		t.Errorf("ZeroFields should return %v, got: %v", expected, zero)
+	}
+
+	nonZero := NonZeroFields(c)
+	expected = []string{"Name", "address.city", "backup"}
+	if !reflect.DeepEqual(nonZero, expected) {
+		t.Errorf("NonZeroFields should return %v, got: %v", expected, nonZero)
+	}
+}
+
+func TestZeroFields_Pointer(t *testing.T) {
+	type Inner struct {
+		A string
+		B int
+	}
+
+	type T struct {
+		Inner *Inner
+	}
+
+	zero := ZeroFields(T{Inner: &Inner{A: "a"}})
+	if !reflect.DeepEqual(zero, []string{"Inner.B"}) {
+		t.Errorf("ZeroFields should return [Inner.B], got: %v", zero)
+	}
+
+	if zero := ZeroFields(T{}); !reflect.DeepEqual(zero, []string{"Inner"}) {
+		t.Errorf("ZeroFields should return [Inner] for a nil pointer, got: %v", zero)
+	}
+}
+
 func TestName(t *testing.T) {
 	type Foo struct {
 		A string
@@ -1387,8 +1441,45 @@ func TestNonStringerTagWithStringOption(t *testing.T) {
 	s.TagName = "json"
 	m := s.Map()
 
+	if m["animal"] != fmt.Sprint(a) {
+		t.Errorf("Value for field Animal should be %s, got: %v", fmt.Sprint(a), m["animal"])
+	}
+
+	s.Strict = true
+	m, err := s.MapErr()
+	if err == nil {
+		t.Error("MapErr should return an error in strict mode")
+	}
+
 	if _, exists := m["animal"]; exists {
-		t.Errorf("Value for field Animal should not exist")
+		t.Errorf("Value for field Animal should not exist in strict mode")
+	}
+
+	if _, err := s.ValuesErr(); err == nil {
+		t.Error("ValuesErr should return an error in strict mode")
+	}
+}
+
+func TestTagWithStringOption_PointerReceiver(t *testing.T) {
+	type Address struct {
+		Person  Person  `structs:"person,string"`
+		Nothing *Person `structs:"nothing,string"`
+	}
+
+	a := Address{Person: Person{Name: "John", Age: 23}}
+
+	m := Map(a)
+	if m["person"] != "John(23)" {
+		t.Errorf("Value for field person should be John(23), got: %v", m["person"])
+	}
+
+	if v, ok := m["nothing"]; !ok || v != nil {
+		t.Errorf("Value for field nothing should be nil, got: %v", v)
+	}
+
+	vs := Values(&a)
+	if vs[0] != "John(23)" {
+		t.Errorf("Value for 1st field (person) should be John(23), got: %v", vs[0])
 	}
 }
 
@@ -1451,3 +1542,409 @@ func TestMap_InterfaceTypeWithMapValue(t *testing.T) {
 
 	_ = Map(a)
 }
+
+func TestMap_ExpandInterfaces(t *testing.T) {
+	type Payload struct {
+		ID int `structs:"id"`
+	}
+
+	type Event struct {
+		Payload interface{}            `structs:"payload"`
+		Items   []interface{}          `structs:"items"`
+		Extra   map[string]interface{} `structs:"extra"`
+	}
+
+	e := Event{
+		Payload: []interface{}{&Payload{ID: 1}},
+		Items:   []interface{}{Payload{ID: 2}, "raw", []interface{}{Payload{ID: 3}}},
+		Extra: map[string]interface{}{
+			"payload": Payload{ID: 4},
+			"nested":  map[string]interface{}{"payload": Payload{ID: 5}},
+			"number":  6,
+		},
+	}
+
+	m := Map(e)
+	if _, ok := m["items"].([]interface{})[0].(Payload); !ok {
+		t.Errorf("Map should not expand interfaces by default, got: %T", m["items"].([]interface{})[0])
+	}
+
+	s := New(e)
+	s.ExpandInterfaces = true
+	m = s.Map()
+
+	expected := map[string]interface{}{
+		"payload": []interface{}{
+			map[string]interface{}{"id": 1},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"id": 2},
+			"raw",
+			[]interface{}{map[string]interface{}{"id": 3}},
+		},
+		"extra": map[string]interface{}{
+			"payload": map[string]interface{}{"id": 4},
+			"nested": map[string]interface{}{
+				"payload": map[string]interface{}{"id": 5},
+			},
+			"number": 6,
+		},
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+}
+
+type mapKeyID int
+
+func (id mapKeyID) String() string {
+	return fmt.Sprintf("id-%d", int(id))
+}
+
+func TestMap_NestedContainersWithStructValues(t *testing.T) {
+	type Item struct {
+		Name string `structs:"name"`
+	}
+
+	type T struct {
+		ByID     map[int]*Item             `structs:"by_id"`
+		ByKey    map[mapKeyID]Item         `structs:"by_key"`
+		Grouped  map[string]map[bool]Item  `structs:"grouped"`
+		Matrix   [][]Item                  `structs:"matrix"`
+		Pair     [2]Item                   `structs:"pair"`
+		Numbers  map[int]string            `structs:"numbers"`
+		Floats   map[float64][]*Item       `structs:"floats"`
+		Preserve map[string]map[string]int `structs:"preserve"`
+	}
+
+	v := T{
+		ByID:     map[int]*Item{1: {Name: "a"}},
+		ByKey:    map[mapKeyID]Item{2: {Name: "b"}},
+		Grouped:  map[string]map[bool]Item{"g": {true: {Name: "c"}}},
+		Matrix:   [][]Item{{{Name: "d"}}},
+		Pair:     [2]Item{{Name: "e"}, {Name: "f"}},
+		Numbers:  map[int]string{3: "three"},
+		Floats:   map[float64][]*Item{1.5: {{Name: "g"}}},
+		Preserve: map[string]map[string]int{"x": {"y": 1}},
+	}
+
+	expected := map[string]interface{}{
+		"by_id":  map[string]interface{}{"1": map[string]interface{}{"name": "a"}},
+		"by_key": map[string]interface{}{"id-2": map[string]interface{}{"name": "b"}},
+		"grouped": map[string]interface{}{
+			"g": map[string]interface{}{"true": map[string]interface{}{"name": "c"}},
+		},
+		"matrix": []interface{}{[]interface{}{map[string]interface{}{"name": "d"}}},
+		"pair": []interface{}{
+			map[string]interface{}{"name": "e"},
+			map[string]interface{}{"name": "f"},
+		},
+		"numbers": map[int]string{3: "three"},
+		"floats": map[string]interface{}{
+			"1.5": []interface{}{map[string]interface{}{"name": "g"}},
+		},
+		"preserve": map[string]map[string]int{"x": {"y": 1}},
+	}
+
+	if m := Map(v); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+}
+
+func TestMap_ConvertContainers(t *testing.T) {
+	type T struct {
+		Numbers map[int]string
+		Nested  map[string][]int
+		Bytes   []byte
+	}
+
+	s := New(T{
+		Numbers: map[int]string{1: "one"},
+		Nested:  map[string][]int{"a": {1, 2}},
+		Bytes:   []byte("raw"),
+	})
+	s.ConvertContainers = true
+
+	expected := map[string]interface{}{
+		"Numbers": map[string]interface{}{"1": "one"},
+		"Nested":  map[string]interface{}{"a": []interface{}{1, 2}},
+		"Bytes":   []byte("raw"),
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+}
+
+func TestNilStructs(t *testing.T) {
+	type Inner struct {
+		A string
+		B int
+	}
+
+	type T struct {
+		Name  string
+		Inner *Inner
+		Raw   *Inner `structs:",omitnested"`
+	}
+
+	v := T{Name: "gopher"}
+	s := New(v)
+
+	expected := map[string]interface{}{
+		"Name":  "gopher",
+		"Inner": nil,
+		"Raw":   (*Inner)(nil),
+	}
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values := []interface{}{"gopher", nil, (*Inner)(nil)}
+	if vs := s.Values(); !reflect.DeepEqual(vs, values) {
+		t.Errorf("Values should return %v, got: %v", values, vs)
+	}
+
+	if !s.HasZero() {
+		t.Error("HasZero should return true for a nil struct")
+	}
+
+	s.NilStructs = OmitNilStructs
+
+	delete(expected, "Inner")
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values = []interface{}{"gopher", (*Inner)(nil)}
+	if vs := s.Values(); !reflect.DeepEqual(vs, values) {
+		t.Errorf("Values should return %v, got: %v", values, vs)
+	}
+
+	if zero := s.ZeroFields(); !reflect.DeepEqual(zero, []string{"Raw"}) {
+		t.Errorf("ZeroFields should return [Raw], got: %v", zero)
+	}
+
+	s.NilStructs = ExpandNilStructs
+
+	expected["Inner"] = map[string]interface{}{"A": "", "B": 0}
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values = []interface{}{"gopher", "", 0, (*Inner)(nil)}
+	if vs := s.Values(); !reflect.DeepEqual(vs, values) {
+		t.Errorf("Values should return %v, got: %v", values, vs)
+	}
+
+	zero := []string{"Inner.A", "Inner.B", "Raw"}
+	if z := s.ZeroFields(); !reflect.DeepEqual(z, zero) {
+		t.Errorf("ZeroFields should return %v, got: %v", zero, z)
+	}
+
+	if s.IsZero() {
+		t.Error("IsZero should return false")
+	}
+
+	if !IsZero(T{}) || !New(T{}).HasZero() {
+		t.Error("A struct with a nil struct should be zero")
+	}
+}
+
+func TestNilStructs_IsZero(t *testing.T) {
+	type Inner struct {
+		A string
+	}
+
+	type T struct {
+		Inner *Inner
+		B     int
+	}
+
+	s := New(T{B: 1})
+	s.NilStructs = OmitNilStructs
+
+	if s.HasZero() {
+		t.Error("HasZero should ignore omitted nil structs")
+	}
+
+	s = New(T{})
+	for _, policy := range []NilStructPolicy{NilStructsAsNil, OmitNilStructs, ExpandNilStructs} {
+		s.NilStructs = policy
+		if !s.IsZero() {
+			t.Errorf("IsZero should return true with policy %d", policy)
+		}
+	}
+}
+
+func TestNilStructs_Recursive(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	s := NewWith(Node{Name: "a", Next: &Node{Name: "b"}}, WithNilStructs(ExpandNilStructs))
+
+	expected := map[string]interface{}{
+		"Name": "a",
+		"Next": map[string]interface{}{"Name": "b", "Next": nil},
+	}
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values := []interface{}{"a", "b", nil}
+	if vs := s.Values(); !reflect.DeepEqual(vs, values) {
+		t.Errorf("Values should return %v, got: %v", values, vs)
+	}
+
+	zero := []string{"Next.Next"}
+	if z := s.ZeroFields(); !reflect.DeepEqual(z, zero) {
+		t.Errorf("ZeroFields should return %v, got: %v", zero, z)
+	}
+
+	if s.IsZero() || !s.HasZero() {
+		t.Error("IsZero should return false and HasZero true")
+	}
+
+	s = NewWith(Node{}, WithNilStructs(ExpandNilStructs))
+	if m := s.Map(); !reflect.DeepEqual(m, map[string]interface{}{"Name": "", "Next": nil}) {
+		t.Errorf("Map should not expand a recursive nil struct, got: %v", m)
+	}
+
+	if !s.IsZero() {
+		t.Error("IsZero should return true for a zero node")
+	}
+}
+
+func TestTagName_Nested(t *testing.T) {
+	type C struct {
+		Name   string `json:"name"`
+		Hidden string `json:"-"`
+		Empty  string `json:",omitempty"`
+	}
+
+	type B struct {
+		C      C
+		Hidden int `json:"-"`
+	}
+
+	type A struct {
+		B B `json:"b"`
+	}
+
+	s := New(A{B: B{C: C{Name: "gopher", Hidden: "x"}, Hidden: 1}})
+	s.TagName = "json"
+
+	expected := map[string]interface{}{
+		"b": map[string]interface{}{
+			"C": map[string]interface{}{"name": "gopher"},
+		},
+	}
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	values := []interface{}{"gopher"}
+	if v := s.Values(); !reflect.DeepEqual(v, values) {
+		t.Errorf("Values should return %v, got: %v", values, v)
+	}
+
+	if s.IsZero() {
+		t.Error("IsZero should return false")
+	}
+
+	zero := []string{"b.C.Empty"}
+	if z := s.ZeroFields(); !reflect.DeepEqual(z, zero) {
+		t.Errorf("ZeroFields should return %v, got: %v", zero, z)
+	}
+}
+
+func TestPointers(t *testing.T) {
+	type T struct {
+		A string
+		b int
+		C int `structs:"-"`
+		D bool
+	}
+
+	v := &T{}
+	pointers := New(v).Pointers()
+
+	if len(pointers) != 2 {
+		t.Fatalf("Pointers should return 2 pointers, got: %d", len(pointers))
+	}
+
+	if pointers[0] != &v.A || pointers[1] != &v.D {
+		t.Errorf("Pointers should return pointers to A and D, got: %v", pointers)
+	}
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("Pointers should panic for a non pointer struct")
+		}
+	}()
+
+	New(T{}).Pointers()
+}
+
+func TestKeys(t *testing.T) {
+	type Meta struct {
+		Version int `structs:"version"`
+	}
+
+	type T struct {
+		Name    string `structs:"name"`
+		Port    int    `structs:"port,omitempty"`
+		hidden  string
+		Ignored bool `structs:"-"`
+		Meta    Meta `structs:",flatten"`
+		Enabled bool
+	}
+
+	v := T{Name: "gopher", hidden: "x"}
+
+	keys := []string{"name", "version", "Enabled"}
+	if k := Keys(v); !reflect.DeepEqual(k, keys) {
+		t.Errorf("Keys should return %v, got: %v", keys, k)
+	}
+
+	m := Map(v)
+	if len(m) != len(keys) {
+		t.Errorf("Keys should return the keys of Map %v, got: %v", m, keys)
+	}
+
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			t.Errorf("Map should contain the key %s", k)
+		}
+	}
+
+	v.Port = 80
+	keys = []string{"name", "port", "version", "Enabled"}
+	if k := Keys(v); !reflect.DeepEqual(k, keys) {
+		t.Errorf("Keys should return %v, got: %v", keys, k)
+	}
+
+	names := []string{"Name", "Port", "Meta", "Enabled"}
+	if n := Names(v); !reflect.DeepEqual(n, names) {
+		t.Errorf("Names should skip unexported fields and return %v, got: %v", names, n)
+	}
+
+	if f := Fields(v); len(f) != len(names) {
+		t.Errorf("Fields should return %d fields, got: %d", len(names), len(f))
+	}
+
+	s := New(v)
+	s.IncludeUnexported = true
+
+	keys = []string{"name", "port", "hidden", "version", "Enabled"}
+	if k := s.Keys(); !reflect.DeepEqual(k, keys) {
+		t.Errorf("Keys should return %v, got: %v", keys, k)
+	}
+
+	if n := s.Names(); len(n) != 5 {
+		t.Errorf("Names should include the unexported field, got: %v", n)
+	}
+}
@@ -0,0 +1,290 @@
+package structs
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var errNotStructOrSlice = errors.New("value is not a struct or a slice of structs")
+
+// TableOptions configures the output of Table.
+type TableOptions struct {
+	// TagName is the struct tag used to name the columns. It defaults to
+	// DefaultTagName.
+	TagName string
+
+	// TimeFormat is the layout used to format time.Time values. It defaults
+	// to time.RFC3339.
+	TimeFormat string
+
+	// MaxWidth truncates every cell to the given number of characters. Zero
+	// means no limit. A "width" option in the "table" tag takes precedence.
+	MaxWidth int
+
+	// Markdown renders the table in the GitHub flavored Markdown format.
+	Markdown bool
+}
+
+// Table renders the given value as a table to w. A struct is rendered as a
+// two-column key/value table, while a slice of structs (either []T or []*T) is
+// rendered with one aligned column per field. Column names are the same as
+// the ones used by Map and nested structs are flattened into dotted columns,
+// the same way as CSVWriter does, so recursive types return an error as well.
+// If opts is nil the default options are used.
+//
+// The "table" tag can be used to customize a single column. A tag value with
+// the content of "-" ignores that particular field. Example:
+//
+//   // Field is not rendered
+//   Field string `table:"-"`
+//
+// A tag value with the option of "width" truncates the column to the given
+// number of characters. Example:
+//
+//   // Field is truncated to 20 characters
+//   Field string `table:"width=20"`
+func Table(w io.Writer, v interface{}, opts *TableOptions) error {
+	if opts == nil {
+		opts = &TableOptions{}
+	}
+
+	tagName := opts.TagName
+	if tagName == "" {
+		tagName = DefaultTagName
+	}
+
+	timeFormat := opts.TimeFormat
+	if timeFormat == "" {
+		timeFormat = time.RFC3339
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	var rows []reflect.Value
+	var t reflect.Type
+	isSlice := false
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		t = rv.Type()
+		rows = []reflect.Value{rv}
+	case reflect.Slice, reflect.Array:
+		t = rv.Type().Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, rv.Index(i))
+		}
+		isSlice = true
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return errNotStructOrSlice
+	}
+
+	all, err := csvColumns(t, tagName)
+	if err != nil {
+		return err
+	}
+
+	var columns []csvColumn
+	var widths []int
+	for _, col := range all {
+		tag := tableTag(t, col.index)
+		if tag == "-" {
+			continue
+		}
+
+		width := opts.MaxWidth
+		if n, ok := tableWidth(tag); ok {
+			width = n
+		}
+
+		columns = append(columns, col)
+		widths = append(widths, width)
+	}
+
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, len(columns))
+		for j, col := range columns {
+			s, err := formatValue(fieldByIndex(row, col.index), timeFormat)
+			if err != nil {
+				return err
+			}
+			cells[i][j] = truncate(sanitizeCell(s), widths[j])
+		}
+	}
+
+	var header []string
+	var body [][]string
+
+	if isSlice {
+		header = make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = col.name
+		}
+		body = cells
+	} else {
+		if opts.Markdown {
+			header = []string{"Field", "Value"}
+		}
+
+		for i, col := range columns {
+			body = append(body, []string{col.name, cells[0][i]})
+		}
+	}
+
+	if opts.Markdown {
+		return writeMarkdownTable(w, header, body)
+	}
+
+	return writePlainTable(w, header, body)
+}
+
+// writePlainTable writes the rows as columns aligned with spaces.
+func writePlainTable(w io.Writer, header []string, body [][]string) error {
+	rows := body
+	if header != nil {
+		rows = append([][]string{header}, body...)
+	}
+
+	widths := columnWidths(rows)
+
+	for _, row := range rows {
+		var line string
+		for i, cell := range row {
+			if i > 0 {
+				line += "  "
+			}
+			line += pad(cell, widths[i])
+		}
+
+		if _, err := io.WriteString(w, strings.TrimRight(line, " ")+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeMarkdownTable writes the rows as a GitHub flavored Markdown table.
+func writeMarkdownTable(w io.Writer, header []string, body [][]string) error {
+	escaped := make([][]string, 0, len(body)+1)
+	for _, row := range append([][]string{header}, body...) {
+		r := make([]string, len(row))
+		for i, cell := range row {
+			r[i] = strings.Replace(cell, "|", "\\|", -1)
+		}
+		escaped = append(escaped, r)
+	}
+
+	widths := columnWidths(escaped)
+
+	sep := make([]string, len(widths))
+	for i, n := range widths {
+		if n < 3 {
+			n = 3
+			widths[i] = n
+		}
+		sep[i] = strings.Repeat("-", n)
+	}
+
+	rows := append([][]string{escaped[0], sep}, escaped[1:]...)
+	for _, row := range rows {
+		line := "|"
+		for i, cell := range row {
+			line += " " + pad(cell, widths[i]) + " |"
+		}
+
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tableTag returns the "table" tag of the field at the given index. A "-"
+// on any parent field hides all of its nested fields too.
+func tableTag(t reflect.Type, index []int) string {
+	for i := 1; i < len(index); i++ {
+		if t.FieldByIndex(index[:i]).Tag.Get("table") == "-" {
+			return "-"
+		}
+	}
+
+	return t.FieldByIndex(index).Tag.Get("table")
+}
+
+// tableWidth returns the value of the "width" option of a table tag.
+func tableWidth(tag string) (int, bool) {
+	for _, opt := range strings.Split(tag, ",") {
+		if !strings.HasPrefix(opt, "width=") {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimPrefix(opt, "width="))
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		return n, true
+	}
+
+	return 0, false
+}
+
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	return widths
+}
+
+// truncate shortens s to n characters, replacing the end with "...". Zero
+// means no limit.
+func truncate(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	r := []rune(s)
+	if n <= 3 {
+		return string(r[:n])
+	}
+
+	return string(r[:n-3]) + "..."
+}
+
+func pad(s string, n int) string {
+	if c := utf8.RuneCountInString(s); c < n {
+		return s + strings.Repeat(" ", n-c)
+	}
+
+	return s
+}
+
+// sanitizeCell replaces line breaks so a cell always fits in a single line.
+func sanitizeCell(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,116 @@
+package structs
+
+import (
+	"bytes"
+	"testing"
+)
+
+type tableServer struct {
+	Name    string `structs:"name"`
+	ID      int    `structs:"id"`
+	Enabled bool   `structs:"enabled"`
+	Token   string `table:"-"`
+	Address struct {
+		Host string `structs:"host" table:"width=8"`
+	} `structs:"address"`
+}
+
+func newTableServers() []tableServer {
+	a := tableServer{Name: "gopher", ID: 1, Enabled: true, Token: "secret"}
+	a.Address.Host = "localhost.localdomain"
+
+	b := tableServer{Name: "arslan", ID: 123456}
+	b.Address.Host = "example"
+
+	return []tableServer{a, b}
+}
+
+func TestTable_Slice(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Table(&buf, newTableServers(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "" +
+		"name    id      enabled  address.host\n" +
+		"gopher  1       true     local...\n" +
+		"arslan  123456  false    example\n"
+
+	if buf.String() != expected {
+		t.Errorf("Table should write\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestTable_Struct(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Table(&buf, &newTableServers()[1], nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "" +
+		"name          arslan\n" +
+		"id            123456\n" +
+		"enabled       false\n" +
+		"address.host  example\n"
+
+	if buf.String() != expected {
+		t.Errorf("Table should write\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestTable_Markdown(t *testing.T) {
+	type T struct {
+		A string
+		B string
+	}
+
+	var buf bytes.Buffer
+	opts := &TableOptions{Markdown: true, MaxWidth: 6}
+	if err := Table(&buf, []*T{{A: "a|b", B: "long value"}}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "" +
+		"| A    | B      |\n" +
+		"| ---- | ------ |\n" +
+		"| a\\|b | lon... |\n"
+
+	if buf.String() != expected {
+		t.Errorf("Table should write\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := Table(&buf, T{A: "x", B: "y"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "" +
+		"| Field | Value |\n" +
+		"| ----- | ----- |\n" +
+		"| A     | x     |\n" +
+		"| B     | y     |\n"
+
+	if buf.String() != expected {
+		t.Errorf("Table should write\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestTable_NonStruct(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Table(&buf, []string{"foo"}, nil); err != errNotStructOrSlice {
+		t.Errorf("Table should return %v for a slice of strings, got: %v", errNotStructOrSlice, err)
+	}
+
+	if err := Table(&buf, 42, nil); err != errNotStructOrSlice {
+		t.Errorf("Table should return %v for an int, got: %v", errNotStructOrSlice, err)
+	}
+}
+
+func TestTable_RecursiveType(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Table(&buf, []csvNode{{Name: "a"}}, nil); err == nil {
+		t.Error("Table should return an error for a recursive type")
+	}
+}
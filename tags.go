@@ -1,6 +1,10 @@
 package structs
 
-import "strings"
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
 
 // tagOptions contains a slice of tag options
 type tagOptions []string
@@ -16,6 +20,19 @@ func (t tagOptions) Has(opt string) bool {
 	return false
 }
 
+// Value returns the value of an option in the form of "opt=value". The boolean
+// returns true if the option is available.
+func (t tagOptions) Value(opt string) (string, bool) {
+	prefix := opt + "="
+	for _, tagOpt := range t {
+		if strings.HasPrefix(tagOpt, prefix) {
+			return tagOpt[len(prefix):], true
+		}
+	}
+
+	return "", false
+}
+
 // parseTag splits a struct field's tag into its name and a list of options
 // which comes after a name. A tag is in the form of: "name,option1,option2".
 // The name can be neglectected.
@@ -30,3 +47,60 @@ func parseTag(tag string) (string, tagOptions) {
 	res := strings.Split(tag, ",")
 	return res[0], res[1:]
 }
+
+// parseStructTag returns all key/value pairs of a struct field's tag in the
+// conventional format of `key:"value" key2:"value2"`. Parsing stops at the
+// first malformed pair, the same way as reflect.StructTag.Lookup does.
+func parseStructTag(tag reflect.StructTag) map[string]string {
+	tags := make(map[string]string)
+
+	for tag != "" {
+		// skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// scan to colon, a space, a quote or a control character is a
+		// syntax error
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		// scan quoted string to find value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		qvalue := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			break
+		}
+
+		// the first value of a key wins, the same as in Lookup
+		if _, ok := tags[name]; !ok {
+			tags[name] = value
+		}
+	}
+
+	return tags
+}
@@ -1,6 +1,9 @@
 package structs
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestParseTag_Name(t *testing.T) {
 	tags := []struct {
@@ -44,3 +47,45 @@ func TestParseTag_Opts(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTag_OptValue(t *testing.T) {
+	tags := []struct {
+		tag   string
+		value string
+		has   bool
+	}{
+		{"name", "", false},
+		{"name,format", "", false},
+		{"name,format=", "", true},
+		{"name,format=hex", "hex", true},
+		{",omitempty,format=2006-01-02", "2006-01-02", true},
+		{"name,formats=hex", "", false},
+	}
+
+	for _, tag := range tags {
+		_, opts := parseTag(tag.tag)
+
+		value, ok := opts.Value("format")
+		if ok != tag.has || value != tag.value {
+			t.Errorf("Tag opts should have format %q: %#v", tag.value, tag)
+		}
+	}
+}
+
+func TestParseStructTag(t *testing.T) {
+	tag := reflect.StructTag(`structs:"name,omitempty" json:"name" doc:"a \"quoted\" value" json:"other"`)
+
+	expected := map[string]string{
+		"structs": "name,omitempty",
+		"json":    "name",
+		"doc":     `a "quoted" value`,
+	}
+
+	if tags := parseStructTag(tag); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("parseStructTag should return %v, got: %v", expected, tags)
+	}
+
+	if tags := parseStructTag(`json:"ok" broken`); !reflect.DeepEqual(tags, map[string]string{"json": "ok"}) {
+		t.Errorf("parseStructTag should stop at a malformed pair, got: %v", tags)
+	}
+}
@@ -0,0 +1,132 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// readValue returns the underlying value of v. Values of unexported fields
+// can't be accessed with reflect.Value.Interface, so a copy of them is
+// returned instead. The copy can be read, but modifying it never changes the
+// original value. Values which can't be copied without the unsafe package,
+// such as structs with unexported fields of their own (ie: time.Time), are
+// returned in their fmt representation.
+func readValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.CanInterface() {
+		return v.Interface()
+	}
+
+	c, ok := copyValue(v, make(map[uintptr]reflect.Value))
+	if !ok {
+		return fmt.Sprint(v)
+	}
+
+	return c.Interface()
+}
+
+// copyValue returns a deep copy of v which can be accessed with Interface. The
+// boolean is false if v can't be copied. The seen map contains the copies of
+// the pointers copied so far, so shared and cyclic pointers are preserved.
+func copyValue(v reflect.Value, seen map[uintptr]reflect.Value) (reflect.Value, bool) {
+	t := v.Type()
+	c := reflect.New(t).Elem()
+
+	switch v.Kind() {
+	case reflect.Bool:
+		c.SetBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.SetInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		c.SetUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		c.SetFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c.SetComplex(v.Complex())
+	case reflect.String:
+		c.SetString(v.String())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return c, true
+		}
+
+		if p, ok := seen[v.Pointer()]; ok {
+			return p, true
+		}
+
+		p := reflect.New(t.Elem())
+		seen[v.Pointer()] = p
+
+		e, ok := copyValue(v.Elem(), seen)
+		if !ok {
+			return c, false
+		}
+		p.Elem().Set(e)
+		c.Set(p)
+	case reflect.Interface:
+		if v.IsNil() {
+			return c, true
+		}
+
+		e, ok := copyValue(v.Elem(), seen)
+		if !ok {
+			return c, false
+		}
+		c.Set(e)
+	case reflect.Slice:
+		if v.IsNil() {
+			return c, true
+		}
+
+		c.Set(reflect.MakeSlice(t, v.Len(), v.Len()))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			e, ok := copyValue(v.Index(i), seen)
+			if !ok {
+				return c, false
+			}
+			c.Index(i).Set(e)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return c, true
+		}
+
+		c.Set(reflect.MakeMap(t))
+		for _, k := range v.MapKeys() {
+			ck, ok := copyValue(k, seen)
+			if !ok {
+				return c, false
+			}
+
+			cv, ok := copyValue(v.MapIndex(k), seen)
+			if !ok {
+				return c, false
+			}
+
+			c.SetMapIndex(ck, cv)
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			// unexported fields of the copy can't be set
+			if t.Field(i).PkgPath != "" {
+				return c, false
+			}
+
+			e, ok := copyValue(v.Field(i), seen)
+			if !ok {
+				return c, false
+			}
+			c.Field(i).Set(e)
+		}
+	default:
+		// functions, channels and unsafe pointers can't be copied
+		return c, false
+	}
+
+	return c, true
+}
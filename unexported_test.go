@@ -0,0 +1,141 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type unexportedInner struct {
+	id    int
+	Label string
+}
+
+type unexportedOuter struct {
+	Name    string
+	secret  string
+	tags    []string
+	inner   unexportedInner
+	ptr     *unexportedInner
+	created time.Time
+}
+
+func newUnexportedOuter() *unexportedOuter {
+	return &unexportedOuter{
+		Name:    "gopher",
+		secret:  "s3cr3t",
+		tags:    []string{"a", "b"},
+		inner:   unexportedInner{id: 1, Label: "inner"},
+		ptr:     &unexportedInner{id: 2, Label: "ptr"},
+		created: time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestIncludeUnexported_Map(t *testing.T) {
+	o := newUnexportedOuter()
+
+	if m := Map(o); len(m) != 1 {
+		t.Errorf("Map should only include exported fields by default, got: %v", m)
+	}
+
+	s := New(o)
+	s.IncludeUnexported = true
+	m := s.Map()
+
+	expected := map[string]interface{}{
+		"Name":   "gopher",
+		"secret": "s3cr3t",
+		"tags":   []string{"a", "b"},
+		"inner": map[string]interface{}{
+			"id":    1,
+			"Label": "inner",
+		},
+		"ptr": map[string]interface{}{
+			"id":    2,
+			"Label": "ptr",
+		},
+	}
+
+	created := m["created"]
+	delete(m, "created")
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should return %v, got: %v", expected, m)
+	}
+
+	if created == nil {
+		t.Error("Map should include structs without exported fields, such as time.Time")
+	}
+
+	// the values are copies, modifying them doesn't change the original
+	m["tags"].([]string)[0] = "changed"
+	if o.tags[0] != "a" {
+		t.Error("Map should return copies of unexported values")
+	}
+}
+
+func TestIncludeUnexported_Values(t *testing.T) {
+	type T struct {
+		A string
+		b int
+		c unexportedInner
+	}
+
+	s := New(T{A: "a", b: 2, c: unexportedInner{id: 3, Label: "c"}})
+	s.IncludeUnexported = true
+
+	expected := []interface{}{"a", 2, 3, "c"}
+	if v := s.Values(); !reflect.DeepEqual(v, expected) {
+		t.Errorf("Values should return %v, got: %v", expected, v)
+	}
+}
+
+func TestIncludeUnexported_Fields(t *testing.T) {
+	s := New(newUnexportedOuter())
+	s.IncludeUnexported = true
+
+	f := s.Field("secret")
+	if f.Value() != "s3cr3t" {
+		t.Errorf("Value should return the unexported value, got: %v", f.Value())
+	}
+
+	if f.IsZero() {
+		t.Error("IsZero should return false for an initialized unexported field")
+	}
+
+	if err := f.Set("other"); err != errNotExported {
+		t.Errorf("Set should return %v for unexported fields, got: %v", errNotExported, err)
+	}
+
+	for _, field := range s.Fields() {
+		// must not panic
+		_ = field.Value()
+	}
+
+	names := s.Names()
+	if len(names) != 6 {
+		t.Errorf("Names should return all fields, got: %v", names)
+	}
+
+	id := s.Field("inner").Field("id")
+	if id.Value() != 1 {
+		t.Errorf("Value of a nested unexported field should be 1, got: %v", id.Value())
+	}
+}
+
+func TestIncludeUnexported_IsZero(t *testing.T) {
+	type T struct {
+		A string
+		b string
+	}
+
+	s := New(T{b: "b"})
+	if !s.IsZero() {
+		t.Error("IsZero should ignore unexported fields by default")
+	}
+
+	s.IncludeUnexported = true
+	if s.IsZero() {
+		t.Error("IsZero should check unexported fields with IncludeUnexported")
+	}
+}
@@ -0,0 +1,118 @@
+package structs
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	zeroFuncsMu sync.RWMutex
+	zeroFuncs   = make(map[reflect.Type]func(reflect.Value) bool)
+
+	isZeroerType = reflect.TypeOf((*isZeroer)(nil)).Elem()
+)
+
+// isZeroer is implemented by types which define their own zero value, such as
+// time.Time.
+type isZeroer interface {
+	IsZero() bool
+}
+
+// RegisterZeroFunc registers fn as the function which decides whether values
+// of the type t are zero values. It's used by IsZero, HasZero, Field.IsZero
+// and the "omitempty" option. Registering a nil fn removes the function for
+// the given type. Example:
+//
+//   // a Money value is zero if its amount is zero, regardless of the currency
+//   structs.RegisterZeroFunc(reflect.TypeOf(Money{}), func(v reflect.Value) bool {
+//       return v.Interface().(Money).Amount == 0
+//   })
+func RegisterZeroFunc(t reflect.Type, fn func(v reflect.Value) bool) {
+	zeroFuncsMu.Lock()
+	defer zeroFuncsMu.Unlock()
+
+	if fn == nil {
+		delete(zeroFuncs, t)
+		return
+	}
+
+	zeroFuncs[t] = fn
+}
+
+// isZero returns true if v is a zero value. A function registered with
+// RegisterZeroFunc takes precedence, followed by an "IsZero() bool" method
+// implemented by the value's type or its pointer type. Otherwise the value is
+// compared with the zero value of its type, so a pointer is only zero if it's
+// nil.
+func isZero(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	if zero, ok := customZero(v); ok {
+		return zero
+	}
+
+	return v.IsZero()
+}
+
+// hasCustomZero returns true if the zero value of t is decided by a registered
+// function or an "IsZero() bool" method instead of its fields.
+func hasCustomZero(t reflect.Type) bool {
+	zeroFuncsMu.RLock()
+	_, ok := zeroFuncs[t]
+	zeroFuncsMu.RUnlock()
+
+	return ok || t.Implements(isZeroerType) || reflect.PtrTo(t).Implements(isZeroerType)
+}
+
+// customZero returns whether v is zero according to its registered function
+// or its "IsZero() bool" method. The returned boolean reports whether any of
+// them exists. Pointers are never checked with them, a non-nil pointer is not
+// zero even if it points to a zero value.
+func customZero(v reflect.Value) (bool, bool) {
+	if v.Kind() == reflect.Ptr {
+		return false, false
+	}
+
+	t := v.Type()
+
+	zeroFuncsMu.RLock()
+	fn, ok := zeroFuncs[t]
+	zeroFuncsMu.RUnlock()
+
+	if ok {
+		// the function may call Interface, which panics for values of
+		// unexported fields
+		return fn(readable(v)), true
+	}
+
+	if !v.CanInterface() {
+		return false, false
+	}
+
+	if t.Implements(isZeroerType) {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice:
+			// don't call the method on nil values, they may not handle it
+			if v.IsNil() {
+				return true, true
+			}
+		}
+
+		return v.Interface().(isZeroer).IsZero(), true
+	}
+
+	if reflect.PtrTo(t).Implements(isZeroerType) {
+		// the method has a pointer receiver, so call it on an addressable copy
+		if !v.CanAddr() {
+			c := reflect.New(t).Elem()
+			c.Set(v)
+			v = c
+		}
+
+		return v.Addr().Interface().(isZeroer).IsZero(), true
+	}
+
+	return false, false
+}
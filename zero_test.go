@@ -0,0 +1,145 @@
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type zeroMoney struct {
+	Amount   int
+	Currency string
+}
+
+type zeroPtrReceiver struct {
+	Value string
+}
+
+func (z *zeroPtrReceiver) IsZero() bool {
+	return z.Value == "" || z.Value == "none"
+}
+
+func TestIsZero_Time(t *testing.T) {
+	type T struct {
+		At time.Time
+	}
+
+	if !IsZero(T{}) {
+		t.Error("IsZero should return true for a zero time")
+	}
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	if !IsZero(T{At: time.Time{}.In(loc)}) {
+		t.Error("IsZero should return true for a zero time in a non UTC location")
+	}
+
+	if IsZero(T{At: time.Now()}) {
+		t.Error("IsZero should return false for a non zero time")
+	}
+
+	if HasZero(T{At: time.Now()}) {
+		t.Error("HasZero should return false for a non zero time")
+	}
+}
+
+func TestIsZero_IsZeroMethod(t *testing.T) {
+	type T struct {
+		Z zeroPtrReceiver
+	}
+
+	if !IsZero(T{Z: zeroPtrReceiver{Value: "none"}}) {
+		t.Error("IsZero should use the IsZero method with a pointer receiver")
+	}
+
+	if !New(&T{Z: zeroPtrReceiver{Value: "none"}}).Field("Z").IsZero() {
+		t.Error("Field.IsZero should use the IsZero method with a pointer receiver")
+	}
+
+	type O struct {
+		Z zeroPtrReceiver `structs:",omitempty"`
+	}
+
+	if m := Map(O{Z: zeroPtrReceiver{Value: "none"}}); len(m) != 0 {
+		t.Errorf("Map should omit values which are zero by their IsZero method, got: %v", m)
+	}
+}
+
+func TestIsZero_TimePointer(t *testing.T) {
+	type T struct {
+		At *time.Time `structs:",omitempty"`
+	}
+
+	// a non-nil pointer is not zero, even if it points to a zero time
+	v := T{At: &time.Time{}}
+
+	if m := Map(v); len(m) != 1 {
+		t.Errorf("Map should keep a non-nil pointer to a zero time, got: %v", m)
+	}
+
+	if HasZero(v) {
+		t.Error("HasZero should return false for a non-nil pointer to a zero time")
+	}
+
+	if IsZero(v) {
+		t.Error("IsZero should return false for a non-nil pointer to a zero time")
+	}
+
+	if !IsZero(T{}) || !HasZero(T{}) {
+		t.Error("A nil pointer should be zero")
+	}
+}
+
+func TestRegisterZeroFunc(t *testing.T) {
+	typ := reflect.TypeOf(zeroMoney{})
+	RegisterZeroFunc(typ, func(v reflect.Value) bool {
+		return v.Interface().(zeroMoney).Amount == 0
+	})
+	defer RegisterZeroFunc(typ, nil)
+
+	type T struct {
+		Price zeroMoney `structs:",omitempty"`
+	}
+
+	v := T{Price: zeroMoney{Currency: "EUR"}}
+
+	if !IsZero(v) {
+		t.Error("IsZero should use the registered zero function")
+	}
+
+	if !HasZero(v) {
+		t.Error("HasZero should use the registered zero function")
+	}
+
+	if vals := Values(v); len(vals) != 0 {
+		t.Errorf("Values should omit values which are zero by the registered function, got: %v", vals)
+	}
+
+	RegisterZeroFunc(typ, nil)
+	if IsZero(v) {
+		t.Error("IsZero should not use a removed zero function")
+	}
+}
+
+func TestRegisterZeroFunc_Unexported(t *testing.T) {
+	typ := reflect.TypeOf(zeroMoney{})
+	RegisterZeroFunc(typ, func(v reflect.Value) bool {
+		return v.Interface().(zeroMoney).Amount == 0
+	})
+	defer RegisterZeroFunc(typ, nil)
+
+	type T struct {
+		Name  string
+		price zeroMoney
+	}
+
+	s := New(T{Name: "gopher", price: zeroMoney{Currency: "EUR"}})
+	s.IncludeUnexported = true
+
+	if !s.HasZero() {
+		t.Error("HasZero should use the registered zero function for an unexported field")
+	}
+
+	if !s.Field("price").IsZero() {
+		t.Error("Field.IsZero should use the registered zero function for an unexported field")
+	}
+}